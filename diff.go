@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/godknowsiamgood/oapi3gen/spec"
+)
+
+// diffSpecs compares two versions of spec and prints found changes,
+// it returns true if any of changes is breaking. Specs are validated against OpenAPI only,
+// so specs rejected by generator are compared as well
+func diffSpecs(oldContent []byte, newContent []byte, out io.Writer) (bool, error) {
+	oldSpec, err := loadSpec(oldContent)
+	if err != nil {
+		return false, fmt.Errorf("old spec: %v", err)
+	}
+	newSpec, err := loadSpec(newContent)
+	if err != nil {
+		return false, fmt.Errorf("new spec: %v", err)
+	}
+
+	changes := spec.Diff(oldSpec, newSpec)
+	for _, change := range changes {
+		_, _ = fmt.Fprintln(out, change)
+	}
+
+	return spec.HasBreakingChanges(changes), nil
+}
+
+// runDiff implements `oapi3gen diff old.yaml new.yaml` command and returns process exit code:
+// 0 if there are no breaking changes, 1 if there are and 2 on failure
+func runDiff(args []string, out io.Writer) int {
+	if len(args) != 2 {
+		logError("usage: oapi3gen diff old.yaml new.yaml")
+		return 2
+	}
+
+	oldContent, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		logError("file not found: %v", err)
+		return 2
+	}
+	newContent, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		logError("file not found: %v", err)
+		return 2
+	}
+
+	hasBreakingChanges, err := diffSpecs(oldContent, newContent, out)
+	if err != nil {
+		logError("%v", err)
+		return 2
+	}
+	if hasBreakingChanges {
+		return 1
+	}
+
+	return 0
+}
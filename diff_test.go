@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldContent, _ := ioutil.ReadFile("./testdata/diff/old.yaml")
+	newContent, _ := ioutil.ReadFile("./testdata/diff/new.yaml")
+	changes, _ := ioutil.ReadFile("./testdata/diff/changes.txt")
+
+	out := new(bytes.Buffer)
+	hasBreakingChanges, err := diffSpecs(oldContent, newContent, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasBreakingChanges {
+		t.Error("breaking changes are not detected")
+	}
+	if out.String() != string(changes) {
+		t.Errorf("unexpected changes:\n%v", out.String())
+	}
+
+	out.Reset()
+	hasBreakingChanges, err = diffSpecs(oldContent, oldContent, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasBreakingChanges || out.Len() != 0 {
+		t.Errorf("unexpected changes of the same spec:\n%v", out.String())
+	}
+}
+
+func TestDiffNotGeneratedSpec(t *testing.T) {
+	content := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Security
+paths:
+  /pets:
+    get:
+      security:
+        - apiKey: []
+      responses:
+        '204':
+          description: No content
+`)
+	out := new(bytes.Buffer)
+	hasBreakingChanges, err := diffSpecs(content, content, out)
+	if err != nil || hasBreakingChanges || out.Len() != 0 {
+		t.Errorf("unexpected diff %v: %v", err, out.String())
+	}
+}
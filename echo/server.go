@@ -1,7 +1,6 @@
 package echo
 
 import (
-	"fmt"
 	"github.com/godknowsiamgood/oapi3gen/spec"
 	"strconv"
 	"strings"
@@ -39,15 +38,47 @@ func (s *Server) TemplateFunctions() template.FuncMap {
 func (s *Server) FieldTags(context string, name string, field spec.Schema, parent spec.Schema) string {
 	var tags []string
 
-	if context == spec.PropertiesContextComponents {
+	if context == spec.PropertiesContextParameters {
+		// properties of object parameters are serialized by names
+		tags = append(tags, "json:\""+name+"\"")
+		tags = append(tags, s.getParameterValidationTags(field, !parent.IsFieldOptional(name))...)
+	} else if context == spec.PropertiesContextComponents || context == spec.PropertiesContextRequestComponents {
 		omitempty := ""
 		if parent.IsFieldOptional(name) {
 			omitempty = ",omitempty"
 		}
 		tags = append(tags, "json:\""+name+omitempty+"\"")
+		if s.Spec.HasXMLContent() {
+			tags = append(tags, "xml:\""+name+omitempty+"\"")
+		}
+	} else if context == spec.PropertiesContextRequestBody && field.IsFile() {
+		tags = append(tags, "form:\""+name+"\"")
+		if field.EncodingContentType != "" {
+			tags = append(tags, "content-type:\""+strings.ReplaceAll(field.EncodingContentType, " ", "")+"\"")
+		}
+		maximumLength := field.MaximumLength
+		if field.Type.IsArray() {
+			maximumLength = field.Items.MaximumLength
+		}
+		if maximumLength != nil {
+			tags = append(tags, "max-size:\""+strconv.Itoa(*maximumLength)+"\"")
+		}
+		if !parent.IsFieldOptional(name) {
+			tags = append(tags, "validate:\"required\"")
+		}
 	} else if context == spec.PropertiesContextRequestBody {
 		tags = append(tags, "form:\""+name+"\"")
-		tags = append(tags, getValidationAndDefaultTagsForInputSchema(field, !parent.IsFieldOptional(name))...)
+		if s.Spec.HasXMLContent() {
+			tags = append(tags, "xml:\""+name+"\"")
+		}
+		if field.IsNullableValue() {
+			// nullable types are maps, so only presence of value is validated
+			if !parent.IsFieldOptional(name) {
+				tags = append(tags, "validate:\"required\"")
+			}
+		} else {
+			tags = append(tags, getValidationTagsForInputSchema(field, !parent.IsFieldOptional(name), false)...)
+		}
 	}
 
 	if len(tags) == 0 {
@@ -64,11 +95,19 @@ func (s *Server) OperationParameterTags(param spec.Parameter) string {
 	switch param.In {
 	case "path":
 		tags = append(tags, "param:\""+param.Name+"\"")
-	case "query":
-		tags = append(tags, "query:\""+param.Name+"\"")
+	case "query", "header", "cookie":
+		tags = append(tags, param.In+":\""+param.Name+"\"")
 	}
 
-	tags = append(tags, getValidationAndDefaultTagsForInputSchema(param.Schema, param.Required)...)
+	// serialization, defaults depend on location
+	if param.GetStyle() != param.GetDefaultStyle() {
+		tags = append(tags, "style:\""+param.GetStyle()+"\"")
+	}
+	if param.IsExplode() != (param.GetStyle() == "form") {
+		tags = append(tags, "explode:\""+strconv.FormatBool(param.IsExplode())+"\"")
+	}
+
+	tags = append(tags, s.getParameterValidationTags(param.Schema, param.Required)...)
 
 	if len(tags) == 0 {
 		return ""
@@ -77,20 +116,65 @@ func (s *Server) OperationParameterTags(param spec.Parameter) string {
 	return "`" + strings.Join(tags, " ") + "`"
 }
 
-func getValidationAndDefaultTagsForInputSchema(schema spec.Schema, isRequired bool) []string {
-	var tags []string
+// getParameterValidationTags returns tags of parameter or property of object parameter,
+// presence of required numbers and booleans is checked while binding since validator
+// treats their zero values as absent
+func (s *Server) getParameterValidationTags(schema spec.Schema, isRequired bool) []string {
+	resolved := s.Spec.ResolveSchema(schema)
+	isPresenceChecked := isRequired && !resolved.IsNullableValue() && (resolved.IsNumeric() || resolved.Type == "boolean")
 
-	// defaults
-	if schema.Default != nil {
-		tags = append(tags, fmt.Sprintf("default:\"%v\"", *schema.Default))
+	var tags []string
+	if isPresenceChecked {
+		tags = append(tags, "required:\"true\"")
 	}
+	return append(tags, getValidationTagsForInputSchema(schema, isRequired, isPresenceChecked)...)
+}
+
+func getValidationTagsForInputSchema(schema spec.Schema, isRequired bool, isPresenceChecked bool) []string {
+	var tags []string
 
-	// validation
 	var validations []string
-	if isRequired {
+	if isRequired && !isPresenceChecked {
 		validations = append(validations, "required")
 	}
 
+	if schemaValidations := getSchemaValidations(schema); len(schemaValidations) > 0 {
+		if !isRequired && !schema.HasDefault() {
+			// absent optional values are not validated, values with defaults are always set
+			validations = append(validations, "omitempty")
+		}
+		validations = append(validations, schemaValidations...)
+	}
+	if len(validations) > 0 {
+		tags = append(tags, "validate:\""+strings.Join(validations, ",")+"\"")
+	}
+
+	return tags
+}
+
+// getSchemaValidations returns validator rules of schema, rules of array items follow dive
+func getSchemaValidations(schema spec.Schema) []string {
+	var validations []string
+
+	if schema.Type.IsArray() {
+		if schema.MinimumItems != nil {
+			validations = append(validations, "min="+strconv.Itoa(*schema.MinimumItems))
+		}
+		if schema.MaximumItems != nil {
+			validations = append(validations, "max="+strconv.Itoa(*schema.MaximumItems))
+		}
+		if schema.UniqueItems {
+			validations = append(validations, "unique")
+		}
+		if schema.Items != nil {
+			if itemValidations := getSchemaValidations(*schema.Items); len(itemValidations) > 0 {
+				validations = append(validations, "dive")
+				validations = append(validations, itemValidations...)
+			}
+		}
+		return validations
+	}
+
 	if len(schema.Enum) > 0 {
 		validations = append(validations, "oneof="+strings.Join(schema.Enum, " "))
 	}
@@ -106,9 +190,6 @@ func getValidationAndDefaultTagsForInputSchema(schema spec.Schema, isRequired bo
 	if schema.MaximumLength != nil {
 		validations = append(validations, "max="+strconv.Itoa(*schema.MaximumLength))
 	}
-	if len(validations) > 0 {
-		tags = append(tags, "validate:\""+strings.Join(validations, ",")+"\"")
-	}
 
-	return tags
+	return validations
 }
@@ -2,7 +2,11 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/goccy/go-yaml"
@@ -10,13 +14,18 @@ import (
 	"github.com/godknowsiamgood/oapi3gen/spec"
 	"github.com/iancoleman/strcase"
 	"golang.org/x/tools/imports"
-	"io/ioutil"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 )
 
+// templates are embedded into binary, so generator works from any directory
+//
+//go:embed base.tmpl echo/server.tmpl
+var templates embed.FS
+
 var (
 	r    = regexp.MustCompile(`\n(\n+)`)
 	repl = []byte("\n")
@@ -26,18 +35,19 @@ func preprocess(b string) string {
 	return string(r.ReplaceAll([]byte(b), repl))
 }
 
-func generate(yamlContent []byte, serverName string) ([]byte, error) {
+// loadSpec validates spec content against OpenAPI and parses it into spec model
+func loadSpec(yamlContent []byte) (spec.Spec, error) {
 	if isVerbose {
 		log("Validating spec for yaml file (%v bytes)...", len(yamlContent))
 	}
 
 	doc, err := openapi3.NewLoader().LoadFromData(yamlContent)
 	if err != nil {
-		return nil, fmt.Errorf("schema parsing failed: %v", err)
+		return spec.Spec{}, fmt.Errorf("schema parsing failed: %v", err)
 	}
 
 	if err := doc.Validate(context.TODO()); err != nil {
-		return nil, fmt.Errorf("schema validation failed: %v", err)
+		return spec.Spec{}, fmt.Errorf("schema validation failed: %v", err)
 	}
 
 	if isVerbose {
@@ -47,32 +57,93 @@ func generate(yamlContent []byte, serverName string) ([]byte, error) {
 	s := spec.Spec{}
 
 	if err := yaml.Unmarshal(yamlContent, &s); err != nil {
-		return nil, fmt.Errorf("schema parsing failed: %v", err)
+		return spec.Spec{}, fmt.Errorf("schema parsing failed: %v", err)
 	}
 
 	if isVerbose {
 		log("Parsed spec %s", s.Info.Title)
 	}
 
+	return s, nil
+}
+
+// parseSpec loads spec and checks that code can be generated for it
+func parseSpec(yamlContent []byte) (spec.Spec, error) {
+	s, err := loadSpec(yamlContent)
+	if err != nil {
+		return spec.Spec{}, err
+	}
+
+	if err := s.ValidateAllOf(); err != nil {
+		return spec.Spec{}, fmt.Errorf("schema validation failed: %v", err)
+	}
+
+	if err := s.ValidateNullable(); err != nil {
+		return spec.Spec{}, fmt.Errorf("schema validation failed: %v", err)
+	}
+
+	if err := s.ValidateXMLContent(); err != nil {
+		return spec.Spec{}, fmt.Errorf("schema validation failed: %v", err)
+	}
+
+	if err := s.ValidateSecurity(); err != nil {
+		return spec.Spec{}, fmt.Errorf("schema validation failed: %v", err)
+	}
+
+	if err := s.ValidateOperationNames(); err != nil {
+		return spec.Spec{}, fmt.Errorf("schema validation failed: %v", err)
+	}
+
+	return s, nil
+}
+
+// GenerateOptions configures generated code
+type GenerateOptions struct {
+	// Server is name of server implementation, e.g. echo
+	Server string
+	// Mock enables MockController generation
+	Mock bool
+	// Examples enables Example<Schema> and Random<Schema> builders generation
+	Examples bool
+	// EmbedSpec enables embedding of compressed spec into generated code
+	EmbedSpec bool
+	// MountBasePath enables mounting of routes under path of server URL
+	MountBasePath bool
+}
+
+func generate(yamlContent []byte, options GenerateOptions) ([]byte, error) {
+	serverName := options.Server
+
+	s, err := parseSpec(yamlContent)
+	if err != nil {
+		return nil, err
+	}
+
+	s.AssignInlineTypeNames()
+
+	if err := s.ValidateTypeNames(); err != nil {
+		return nil, fmt.Errorf("schema validation failed: %v", err)
+	}
+
 	var server Server
 	switch serverName {
 	case "echo":
 		server = &echo.Server{Spec: s}
 	default:
-		server = DefaultServer{}
+		server = DefaultServer{Spec: s}
 	}
 
 	if isVerbose {
 		log("Loading templates...")
 	}
 
-	baseTemplateFile, _ := ioutil.ReadFile("./base.tmpl")
+	baseTemplateFile, _ := templates.ReadFile("base.tmpl")
 	baseTemplateContent := string(baseTemplateFile)
 
 	var serverTmplReplace string
 	var serverBoilerplateReplace string
 	if serverName != "" {
-		serverTmpl, _ := ioutil.ReadFile("./" + serverName + "/server.tmpl")
+		serverTmpl, _ := templates.ReadFile(serverName + "/server.tmpl")
 		if len(serverTmpl) != 0 {
 			serverTmplReplace = string(serverTmpl)
 			serverBoilerplateReplace = "{{ template \"serverBoilerplate\" . }}"
@@ -92,12 +163,68 @@ func generate(yamlContent []byte, serverName string) ([]byte, error) {
 		"toLowerCamel":            strcase.ToLowerCamel,
 		"dict":                    templateMap,
 		"isNillableSchema":        s.IsNillableSchema,
-		"isOmmitableSchema":       s.IsOmmitableSchema,
 		"isStruct":                s.IsStruct,
+		"isRecursiveSchema":       s.IsRecursiveSchema,
 		"getUnderlyingSchema":     s.GetUnderlyingSchema,
+		"resolveSchema":           s.ResolveSchema,
+		"mergeAllOf":              s.MergeAllOf,
 		"hasGenericErrorResponse": s.HasGenericErrorResponse,
-		"server":                  func() Server { return server },
-		"getContext":              func() string { return objectsContext },
+		"getResponseMediaType":    s.GetResponseMediaType,
+		"getResponseMediaTypes":   s.GetResponseMediaTypes,
+		"getOperationMediaTypes":  s.GetOperationMediaTypes,
+		"isNegotiatedOperation":   s.IsNegotiatedOperation,
+		"hasXMLContent":           s.HasXMLContent,
+		"getOperationSecurity":    s.GetOperationSecurity,
+		"hasRequestVariant":       s.HasRequestVariant,
+		"hasParametersDefaults":   s.HasParametersDefaults,
+		"isPointerParameter":      s.IsPointerParameter,
+		"defaultZeroCheck":        s.GetDefaultZeroCheck,
+		"defaultLiteral":          s.GetDefaultLiteral,
+		"defaultJSON":             s.GetDefaultJSON,
+		"hasDefaults": func(schema spec.Schema) bool {
+			return s.HasDefaults(schema, objectsContext)
+		},
+		"hasNestedDefaults": func(schema spec.Schema) bool {
+			return s.HasNestedDefaults(schema, objectsContext)
+		},
+		"isPointerProperty": func(parent spec.Schema, name string, property spec.Schema) bool {
+			return s.IsPointerProperty(parent, name, property, objectsContext)
+		},
+		"refTypeName": func(ref spec.Ref) string {
+			if objectsContext == spec.PropertiesContextRequestBody || objectsContext == spec.PropertiesContextRequestComponents {
+				return s.GetRequestTypeName(ref)
+			}
+			return ref.GetTypeName()
+		},
+		"inlineTypeName": func(schema spec.Schema) string {
+			return s.GetInlineTypeName(schema, objectsContext)
+		},
+		"exampleLiteral": func(schema spec.Schema) (string, error) {
+			value, ok := s.GetExample(schema)
+			if !ok {
+				return "", nil
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid example: %v", err)
+			}
+			return strconv.Quote(string(data)), nil
+		},
+		"routePath": func(path string, operation spec.Operation) string {
+			if options.MountBasePath {
+				return s.GetOperationBasePath(path, operation) + path
+			}
+			return path
+		},
+		"embeddedSpec": func() ([]string, error) {
+			return encodeSpec(yamlContent)
+		},
+		"nullableTypeName": func(schema spec.Schema) string {
+			return s.GetNullableTypeName(schema, objectsContext)
+		},
+		"server":     func() Server { return server },
+		"options":    func() GenerateOptions { return options },
+		"getContext": func() string { return objectsContext },
 		"setContext": func(c string) string {
 			objectsContext = c
 			return ""
@@ -148,6 +275,28 @@ func generate(yamlContent []byte, serverName string) ([]byte, error) {
 	return formattedSource, nil
 }
 
+// encodeSpec returns gzipped and base64 encoded spec content split into lines
+func encodeSpec(yamlContent []byte) ([]string, error) {
+	const lineLength = 80
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(yamlContent); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+	var lines []string
+	for len(encoded) > lineLength {
+		lines = append(lines, encoded[:lineLength])
+		encoded = encoded[lineLength:]
+	}
+	return append(lines, encoded), nil
+}
+
 func renderImports(sourceRaw []byte, imports []string) []byte {
 	importsStr := strings.Builder{}
 	for _, i := range imports {
@@ -1,28 +1,505 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/godknowsiamgood/oapi3gen/spec"
 )
 
-func Test(t *testing.T) {
-	yamlContent, _ := ioutil.ReadFile("./test/v1/spec.yaml")
-	goContent, _ := ioutil.ReadFile("./test/v1/spec.gocode")
-	out, err := generate(yamlContent, "")
-	if err != nil {
-		t.Error(err)
+var update = flag.Bool("update", false, "update expected code of testdata fixtures")
+
+// fixtureOptions are read from optional options.yaml of fixture directory
+type fixtureOptions struct {
+	Mock      bool `yaml:"mock"`
+	Examples  bool `yaml:"examples"`
+	EmbedSpec bool `yaml:"embedSpec"`
+	BasePath  bool `yaml:"basePath"`
+}
+
+// backends are generated for every fixture, expected code is stored in file of backend
+var backends = []struct {
+	server string
+	file   string
+}{
+	{server: "", file: "spec.gocode"},
+	{server: "echo", file: "spec_echo.gocode"},
+}
+
+// TestGolden generates code for every testdata/<feature>/spec.yaml with every backend,
+// compares it with expected files (rewritten with -update flag), checks that it compiles
+// and runs tests of fixture against it
+func TestGolden(t *testing.T) {
+	specFiles, _ := filepath.Glob("testdata/*/spec.yaml")
+	if len(specFiles) == 0 {
+		t.Fatal("no fixtures found")
+	}
+
+	vetDir := newGeneratedModule(t)
+
+	var vetPackages, testPackages []string
+	for _, specFile := range specFiles {
+		dir := filepath.Dir(specFile)
+		name := filepath.Base(dir)
+
+		t.Run(name, func(t *testing.T) {
+			yamlContent, _ := ioutil.ReadFile(specFile)
+
+			var fixture fixtureOptions
+			if optionsContent, err := ioutil.ReadFile(filepath.Join(dir, "options.yaml")); err == nil {
+				if err := yaml.Unmarshal(optionsContent, &fixture); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, backend := range backends {
+				out, err := generate(yamlContent, GenerateOptions{Server: backend.server, Mock: fixture.Mock, Examples: fixture.Examples, EmbedSpec: fixture.EmbedSpec, MountBasePath: fixture.BasePath})
+				if err != nil {
+					t.Errorf("%v: %v", backend.file, err)
+					continue
+				}
+
+				expectedFile := filepath.Join(dir, backend.file)
+				if *update {
+					if err := ioutil.WriteFile(expectedFile, out, 0644); err != nil {
+						t.Fatal(err)
+					}
+				} else if expected, _ := ioutil.ReadFile(expectedFile); string(expected) != string(out) {
+					t.Errorf("generated code differs from %v:\n%v", expectedFile, unifiedDiff(expectedFile, "generated", string(expected), string(out)))
+				}
+
+				packageName := name + strings.TrimSuffix(strings.TrimPrefix(backend.file, "spec"), ".gocode")
+				packageDir := filepath.Join(vetDir, packageName)
+				if err := os.MkdirAll(packageDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(packageDir, "spec.go"), out, 0644); err != nil {
+					t.Fatal(err)
+				}
+				vetPackages = append(vetPackages, "./"+packageName)
+
+				// runtime tests of generated code are stored next to expected code as <file>_test.gocode
+				testFile := strings.TrimSuffix(expectedFile, ".gocode") + "_test.gocode"
+				if testContent, err := ioutil.ReadFile(testFile); err == nil {
+					if err := ioutil.WriteFile(filepath.Join(packageDir, "spec_test.go"), testContent, 0644); err != nil {
+						t.Fatal(err)
+					}
+					testPackages = append(testPackages, "./"+packageName)
+				}
+			}
+		})
+	}
+
+	if testing.Short() || len(vetPackages) == 0 {
+		return
+	}
+
+	cmd := exec.Command("go", append([]string{"vet"}, vetPackages...)...)
+	cmd.Dir = vetDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("generated code does not pass go vet: %v\n%s", err, out)
+	}
+
+	if len(testPackages) == 0 {
+		return
 	}
-	if string(out) != string(goContent) {
-		t.Errorf("failed")
+	cmd = exec.Command("go", append([]string{"test"}, testPackages...)...)
+	cmd.Dir = vetDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("generated code does not pass its tests: %v\n%s", err, out)
 	}
+}
+
+// newGeneratedModule creates module for generated packages in temporary directory, it requires
+// dependencies of this module in the same versions and refers back to this module with replace directive
+func newGeneratedModule(t *testing.T) string {
+	dir := t.TempDir()
 
-	yamlContent, _ = ioutil.ReadFile("./test/v1/spec.yaml")
-	goContent, _ = ioutil.ReadFile("./test/v1/spec_echo.gocode")
-	out, err = generate(yamlContent, "echo")
+	root, err := os.Getwd()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	modContent, err := ioutil.ReadFile("go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sumContent, err := ioutil.ReadFile("go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moduleLine := regexp.MustCompile(`(?m)^module (.*)$`)
+	modulePath := string(moduleLine.FindSubmatch(modContent)[1])
+	modContent = moduleLine.ReplaceAll(modContent, []byte("module generated"))
+	modContent = append(modContent, fmt.Sprintf("\nrequire %v v0.0.0\n\nreplace %v => %v\n", modulePath, modulePath, root)...)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), modContent, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.sum"), sumContent, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+// unifiedDiff returns difference of texts by lines in unified format with 3 lines of context
+func unifiedDiff(oldName string, newName string, oldText string, newText string) string {
+	const context = 3
+
+	a, b := strings.SplitAfter(oldText, "\n"), strings.SplitAfter(newText, "\n")
+
+	// lcs[i][j] is length of longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
 	}
-	if string(out) != string(goContent) {
-		t.Errorf("failed")
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		kind byte
+		text string
+		i, j int
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], i, j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, line{'+', b[j], i, j})
+			j++
+		default:
+			lines = append(lines, line{'-', a[i], i, j})
+			i++
+		}
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("--- " + oldName + "\n+++ " + newName + "\n")
+	for start := 0; start < len(lines); {
+		if lines[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// hunk spans changed lines separated by less than two contexts
+		from, to := start-context, start
+		if from < 0 {
+			from = 0
+		}
+		for unchanged := 0; to < len(lines) && unchanged <= 2*context; to++ {
+			if lines[to].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		for to > start && lines[to-1].kind == ' ' {
+			to--
+		}
+		if to += context; to > len(lines) {
+			to = len(lines)
+		}
+
+		oldCount, newCount := 0, 0
+		for _, l := range lines[from:to] {
+			if l.kind != '+' {
+				oldCount++
+			}
+			if l.kind != '-' {
+				newCount++
+			}
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", lines[from].i+1, oldCount, lines[from].j+1, newCount))
+		for _, l := range lines[from:to] {
+			sb.WriteString(string(l.kind) + strings.TrimSuffix(l.text, "\n") + "\n")
+		}
+
+		start = to
+	}
+
+	return sb.String()
+}
+
+func TestAllOfConflict(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Conflict
+paths: {}
+components:
+  schemas:
+    A:
+      type: object
+      properties:
+        id:
+          type: string
+    B:
+      allOf:
+        - $ref: '#/components/schemas/A'
+        - type: object
+          properties:
+            id:
+              type: integer
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "#/components/schemas/B: property 'id' has conflicting types string and int64") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInlineTypeNameCollision(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Collision
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          type: object
+          properties:
+            name:
+              type: string
+    Person:
+      type: object
+      properties:
+        address:
+          x-go-name: PetSchemaOwner
+          type: object
+          properties:
+            city:
+              type: string
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "inline object type PetSchemaOwner in #/components/schemas/") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInlineTypeMediaTypesCollision(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Collision
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                owner:
+                  type: object
+                  properties:
+                    name:
+                      type: string
+          application/xml:
+            schema:
+              type: object
+              properties:
+                owner:
+                  type: object
+                  properties:
+                    email:
+                      type: string
+      responses:
+        '204':
+          description: No content
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	expected := "inline object type PostPetsBodyOwner in POST /pets request body is declared differently by media types application/json and application/xml"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAllOfCycle(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Cycle
+paths: {}
+components:
+  schemas:
+    A:
+      allOf:
+        - $ref: '#/components/schemas/B'
+    B:
+      allOf:
+        - $ref: '#/components/schemas/A'
+        - type: object
+          properties:
+            id:
+              type: string
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "includes itself") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUnknownSecurityScheme(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Security
+paths:
+  /pets:
+    get:
+      security:
+        - apiKey: []
+      responses:
+        '204':
+          description: No content
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "GET /pets: unknown security scheme 'apiKey'") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestOperationNameCollision(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Names
+paths:
+  /pets/{petId}:
+    get:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+  /pets/by/{petId}:
+    get:
+      operationId: getPetsByPetId
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "operation name GetPetsByPetId is shared by GET /pets/by/{petId}, GET /pets/{petId}") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestStatusCodesOrder(t *testing.T) {
+	operation := spec.Operation{Responses: map[string]spec.Response{}}
+	for _, code := range []string{"default", "4XX", "404", "200", "2XX", "201", "400"} {
+		operation.Responses[code] = spec.Response{}
+	}
+
+	codes := strings.Join(operation.GetStatusCodes(), " ")
+	if codes != "200 201 2XX 400 404 4XX default" {
+		t.Errorf("unexpected order %v", codes)
+	}
+}
+
+func TestXMLContentMap(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: XML map
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: OK
+          content:
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Pets'
+components:
+  schemas:
+    Pets:
+      type: object
+      properties:
+        labels:
+          $ref: '#/components/schemas/Labels'
+    Labels:
+      type: object
+      additionalProperties:
+        type: string
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "#/components/schemas/Labels is map") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInlineNullable(t *testing.T) {
+	yamlContent := []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Inline nullable
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        tags:
+          type: array
+          nullable: true
+          items:
+            type: string
+`)
+	_, err := generate(yamlContent, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "nullable property tags of #/components/schemas/Pet") {
+		t.Errorf("unexpected error: %v", err)
 	}
 }
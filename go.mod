@@ -4,7 +4,6 @@ go 1.17
 
 require (
 	github.com/Masterminds/semver v1.5.0
-	github.com/creasty/defaults v1.5.2
 	github.com/getkin/kin-openapi v0.79.0
 	github.com/go-playground/validator/v10 v10.4.1
 	github.com/goccy/go-yaml v1.9.4
@@ -12,7 +12,9 @@ type Server interface {
 	FieldTags(context string, name string, field spec.Schema, parent spec.Schema) string
 }
 
-type DefaultServer struct{}
+type DefaultServer struct {
+	Spec spec.Spec
+}
 
 func (s DefaultServer) OperationParameterTags(_ spec.Parameter) string {
 	return ""
@@ -25,13 +27,18 @@ func (s DefaultServer) TemplateFunctions() template.FuncMap {
 func (s DefaultServer) FieldTags(context string, name string, field spec.Schema, parent spec.Schema) string {
 	tags := strings.Builder{}
 
-	tags.WriteString("`json:\"" + name)
-
+	omitempty := ""
 	if parent.IsFieldOptional(name) && context != spec.PropertiesContextParameters && context != spec.PropertiesContextRequestBody {
-		tags.WriteString(",omitempty")
+		omitempty = ",omitempty"
+	}
+
+	tags.WriteString("`json:\"" + name + omitempty + "\"")
+
+	if s.Spec.HasXMLContent() {
+		tags.WriteString(" xml:\"" + name + omitempty + "\"")
 	}
 
-	tags.WriteString("\"`")
+	tags.WriteString("`")
 
 	return tags.String()
 }
@@ -40,9 +40,20 @@ func main() {
 		return
 	}
 
+	switch os.Args[1] {
+	case "diff":
+		os.Exit(runDiff(os.Args[2:], os.Stdout))
+	case "mock-server":
+		os.Exit(runMockServer(os.Args[2:]))
+	}
+
 	serverFlag := flag.String("server", "", "server implementation")
 	outputFlag := flag.String("output", "", "output file")
 	verboseFlag := flag.Bool("verbose", false, "show additional info")
+	mockFlag := flag.Bool("mock", false, "generate MockController")
+	examplesFlag := flag.Bool("examples", false, "generate Example and Random builders of schemas")
+	embedSpecFlag := flag.Bool("embed-spec", false, "embed compressed spec into generated code")
+	basePathFlag := flag.Bool("base-path", false, "mount routes under path of server URL")
 
 	flag.Parse()
 
@@ -59,9 +70,9 @@ func main() {
 		return
 	}
 
-	out, err := generate(specFileData, *serverFlag)
+	out, err := generate(specFileData, GenerateOptions{Server: *serverFlag, Mock: *mockFlag, Examples: *examplesFlag, EmbedSpec: *embedSpecFlag, MountBasePath: *basePathFlag})
 	if err != nil {
-		logError("$v", err)
+		logError("%v", err)
 		return
 	}
 
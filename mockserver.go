@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/goccy/go-yaml"
+	"github.com/godknowsiamgood/oapi3gen/mockserver"
+)
+
+// runMockServer implements `oapi3gen mock-server spec.yaml` command serving fake responses for spec operations
+func runMockServer(args []string) int {
+	flags := flag.NewFlagSet("mock-server", flag.ContinueOnError)
+	addrFlag := flags.String("addr", ":8080", "listen address")
+	overridesFlag := flags.String("overrides", "", "yaml file with responses overrides by operationId or \"METHOD /path\"")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		logError("usage: oapi3gen mock-server [-addr :8080] [-overrides overrides.yaml] spec.yaml")
+		return 2
+	}
+
+	server, err := newMockServer(flags.Arg(0), *overridesFlag)
+	if err != nil {
+		logError("%v", err)
+		return 2
+	}
+
+	log("Serving %v on %v...", flags.Arg(0), *addrFlag)
+	if err := http.ListenAndServe(*addrFlag, server); err != nil {
+		logError("%v", err)
+		return 1
+	}
+
+	return 0
+}
+
+func newMockServer(specFileName string, overridesFileName string) (*mockserver.Server, error) {
+	doc, err := openapi3.NewLoader().LoadFromFile(specFileName)
+	if err != nil {
+		return nil, fmt.Errorf("schema parsing failed: %v", err)
+	}
+
+	if err := doc.Validate(context.TODO()); err != nil {
+		return nil, fmt.Errorf("schema validation failed: %v", err)
+	}
+
+	server, err := mockserver.New(doc)
+	if err != nil {
+		return nil, fmt.Errorf("schema validation failed: %v", err)
+	}
+
+	if overridesFileName != "" {
+		data, err := ioutil.ReadFile(overridesFileName)
+		if err != nil {
+			return nil, fmt.Errorf("file not found: %v", err)
+		}
+		overrides := make(map[string]mockserver.Response)
+		if err := yaml.Unmarshal(data, &overrides); err != nil {
+			return nil, fmt.Errorf("overrides parsing failed: %v", err)
+		}
+		for operation, response := range overrides {
+			server.Override(operation, response)
+		}
+	}
+
+	return server, nil
+}
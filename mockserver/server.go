@@ -0,0 +1,376 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// maxDepth limits nesting of synthesized values of recursive schemas
+const maxDepth = 8
+
+// Response is response served for operation instead of one taken from spec
+type Response struct {
+	Status  int               `yaml:"status" json:"status"`
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	Body    interface{}       `yaml:"body" json:"body"`
+}
+
+// Server serves every operation of spec with examples or values synthesized from response schemas,
+// requests are validated against parameters and request body schemas
+type Server struct {
+	router   routers.Router
+	basePath string
+
+	mu        sync.RWMutex
+	overrides map[string]Response
+}
+
+// New creates fake server for spec, paths are served under base path of first spec server
+func New(doc *openapi3.T) (*Server, error) {
+	basePath := ""
+	if len(doc.Servers) > 0 {
+		if u, err := url.Parse(doc.Servers[0].URL); err == nil {
+			basePath = strings.TrimRight(u.Path, "/")
+		}
+	}
+
+	// requests are matched by path only, so spec servers hosts do not matter
+	routerDoc := *doc
+	routerDoc.Servers = nil
+
+	router, err := legacy.NewRouter(&routerDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{router: router, basePath: basePath, overrides: make(map[string]Response)}, nil
+}
+
+// Override sets response served for operation, operation is identified by operationId
+// or by method and path like "GET /pets/{petId}"
+func (s *Server) Override(operation string, response Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[operation] = response
+}
+
+// Reset removes all response overrides
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = make(map[string]Response)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.basePath != "" {
+		// base path matches whole segments only, e.g. /v1x/pets is not served under /v1
+		path := strings.TrimPrefix(req.URL.Path, s.basePath)
+		if path == req.URL.Path || (path != "" && !strings.HasPrefix(path, "/")) {
+			writeError(w, http.StatusNotFound, "path not found")
+			return
+		}
+		if path == "" {
+			path = "/"
+		}
+		routeReq := req.Clone(req.Context())
+		routeReq.URL.Path = path
+		req = routeReq
+	}
+
+	route, pathParams, err := s.router.FindRoute(req)
+	if err != nil {
+		status := http.StatusNotFound
+		if routeErr, ok := err.(*routers.RouteError); ok && routeErr.Reason == routers.ErrMethodNotAllowed.Error() {
+			status = http.StatusMethodNotAllowed
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	if err := openapi3filter.ValidateRequest(req.Context(), &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if response, ok := s.getOverride(route); ok {
+		writeResponse(w, response.Status, response.Headers, "", response.Body)
+		return
+	}
+
+	status, mediaType, body := Synthesize(route.Operation)
+	writeResponse(w, status, nil, mediaType, body)
+}
+
+func (s *Server) getOverride(route *routers.Route) (Response, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if response, ok := s.overrides[route.Operation.OperationID]; ok && route.Operation.OperationID != "" {
+		return response, true
+	}
+	response, ok := s.overrides[route.Method+" "+route.Path]
+	return response, ok
+}
+
+// Synthesize returns status, media type and body of successful operation response:
+// example of media type, first of named examples or value synthesized from schema
+func Synthesize(operation *openapi3.Operation) (int, string, interface{}) {
+	status, response := getSuccessResponse(operation)
+	if response == nil || len(response.Content) == 0 {
+		return status, "", nil
+	}
+
+	mediaType := getMediaType(response.Content)
+	media := response.Content[mediaType]
+
+	if media.Example != nil {
+		return status, mediaType, media.Example
+	}
+	if len(media.Examples) > 0 {
+		var names []string
+		for name := range media.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if example := media.Examples[names[0]]; example != nil && example.Value != nil {
+			return status, mediaType, example.Value.Value
+		}
+	}
+	if media.Schema == nil {
+		return status, mediaType, nil
+	}
+
+	return status, mediaType, SynthesizeValue(media.Schema.Value)
+}
+
+// getSuccessResponse returns lowest 2xx response, default response is served with 200
+func getSuccessResponse(operation *openapi3.Operation) (int, *openapi3.Response) {
+	var statuses []int
+	for code := range operation.Responses {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Ints(statuses)
+
+	if len(statuses) > 0 {
+		return statuses[0], operation.Responses[strconv.Itoa(statuses[0])].Value
+	}
+	if response := operation.Responses.Default(); response != nil {
+		return http.StatusOK, response.Value
+	}
+
+	return http.StatusOK, nil
+}
+
+// getMediaType prefers JSON media types
+func getMediaType(content openapi3.Content) string {
+	var mediaTypes []string
+	for mediaType := range content {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return mediaType
+		}
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	return mediaTypes[0]
+}
+
+// SynthesizeValue returns value valid against schema, honoring examples, defaults,
+// enums, formats and limits
+func SynthesizeValue(schema *openapi3.Schema) interface{} {
+	return synthesize(schema, 0)
+}
+
+func synthesize(schema *openapi3.Schema, depth int) interface{} {
+	if schema == nil || depth > maxDepth {
+		return nil
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.AllOf) > 0:
+		merged := make(map[string]interface{})
+		for _, inner := range schema.AllOf {
+			if object, ok := synthesize(inner.Value, depth).(map[string]interface{}); ok {
+				for name, value := range object {
+					merged[name] = value
+				}
+			}
+		}
+		for name, value := range synthesizeProperties(schema, depth) {
+			merged[name] = value
+		}
+		return merged
+	case len(schema.OneOf) > 0:
+		return synthesize(schema.OneOf[0].Value, depth)
+	case len(schema.AnyOf) > 0:
+		return synthesize(schema.AnyOf[0].Value, depth)
+	}
+
+	switch schema.Type {
+	case "string":
+		return synthesizeString(schema)
+	case "integer":
+		return int64(synthesizeNumber(schema, 1))
+	case "number":
+		return synthesizeNumber(schema, 0.5)
+	case "boolean":
+		return true
+	case "array":
+		count := 1
+		if schema.MinItems > 1 {
+			count = int(schema.MinItems)
+		}
+		if schema.MaxItems != nil && int(*schema.MaxItems) < count {
+			count = int(*schema.MaxItems)
+		}
+		if depth >= maxDepth/2 && schema.MinItems == 0 {
+			// arrays of deeply nested values are left empty to stop recursion
+			count = 0
+		}
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count && schema.Items != nil; i++ {
+			items = append(items, synthesize(schema.Items.Value, depth+1))
+		}
+		return items
+	}
+
+	return synthesizeProperties(schema, depth)
+}
+
+func synthesizeProperties(schema *openapi3.Schema, depth int) map[string]interface{} {
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	object := make(map[string]interface{})
+	for name, property := range schema.Properties {
+		if property.Value == nil || property.Value.WriteOnly {
+			continue
+		}
+		// optional properties of deeply nested values are skipped to stop recursion
+		if depth >= maxDepth/2 && !required[name] {
+			continue
+		}
+		object[name] = synthesize(property.Value, depth+1)
+	}
+	return object
+}
+
+func synthesizeString(schema *openapi3.Schema) string {
+	var value string
+	switch schema.Format {
+	case "date":
+		value = "2006-01-02"
+	case "date-time":
+		value = "2006-01-02T15:04:05Z"
+	case "email":
+		value = "user@example.com"
+	case "uuid":
+		value = "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		value = "https://example.com"
+	case "hostname":
+		value = "example.com"
+	case "ipv4":
+		value = "127.0.0.1"
+	case "ipv6":
+		value = "::1"
+	case "byte":
+		value = "c3RyaW5n"
+	default:
+		value = "string"
+	}
+
+	for uint64(len(value)) < schema.MinLength {
+		value += "x"
+	}
+	if schema.MaxLength != nil && uint64(len(value)) > *schema.MaxLength {
+		value = value[:*schema.MaxLength]
+	}
+
+	return value
+}
+
+func synthesizeNumber(schema *openapi3.Schema, step float64) float64 {
+	value := float64(0)
+	if schema.Min != nil {
+		value = *schema.Min
+		if schema.ExclusiveMin {
+			value += step
+		}
+	}
+	if schema.Max != nil && value > *schema.Max {
+		value = *schema.Max
+		if schema.ExclusiveMax {
+			value -= step
+		}
+	}
+	return value
+}
+
+func writeResponse(w http.ResponseWriter, status int, headers map[string]string, mediaType string, body interface{}) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	for name, value := range headers {
+		w.Header().Set(name, value)
+	}
+
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	if mediaType == "" {
+		mediaType = w.Header().Get("Content-Type")
+	}
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+	w.Header().Set("Content-Type", mediaType)
+
+	var data []byte
+	if text, ok := body.(string); ok && !strings.Contains(mediaType, "json") {
+		data = []byte(text)
+	} else {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
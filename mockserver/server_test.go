@@ -0,0 +1,174 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const testSpec = `
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Pets
+servers:
+  - url: http://pets.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              example:
+                id: 7
+                name: Rex
+  /pets/{petId}:
+    get:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: Pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      required:
+        - name
+        - kind
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        name:
+          type: string
+          minLength: 8
+        kind:
+          type: string
+          enum: [dog, cat]
+        born:
+          type: string
+          format: date
+        parent:
+          $ref: '#/components/schemas/Pet'
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+func newTestServer(t *testing.T) *Server {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := New(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func serve(server *Server, method string, target string, body string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer(t *testing.T) {
+	server := newTestServer(t)
+
+	rec := serve(server, "GET", "/v1/pets?limit=10", "")
+	var pets []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &pets); rec.Code != http.StatusOK || err != nil || len(pets) != 1 {
+		t.Fatalf("unexpected synthesized response %v: %v", rec.Code, rec.Body.String())
+	}
+	if pet := pets[0]; pet["id"] != float64(1) || pet["name"] != "stringxx" || pet["kind"] != "dog" || pet["born"] != "2006-01-02" {
+		t.Errorf("unexpected synthesized pet: %v", pet)
+	}
+
+	if rec := serve(server, "POST", "/v1/pets", `{"name":"Rex the dog","kind":"dog"}`); rec.Code != http.StatusCreated || strings.TrimSpace(rec.Body.String()) != `{"id":7,"name":"Rex"}` {
+		t.Errorf("unexpected example response %v: %v", rec.Code, rec.Body.String())
+	}
+
+	for _, invalid := range []*httptest.ResponseRecorder{
+		serve(server, "GET", "/v1/pets?limit=1000", ""),
+		serve(server, "POST", "/v1/pets", `{"name":"Rex"}`),
+	} {
+		if invalid.Code != http.StatusBadRequest {
+			t.Errorf("invalid request is accepted: %v %v", invalid.Code, invalid.Body.String())
+		}
+	}
+
+	for _, path := range []string{"/v1/owners", "/v1x/pets", "/pets"} {
+		if rec := serve(server, "GET", path, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("unexpected status of unknown path %v: %v", path, rec.Code)
+		}
+	}
+	if rec := serve(server, "DELETE", "/v1/pets", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected status of unknown method: %v", rec.Code)
+	}
+}
+
+func TestServerOverrides(t *testing.T) {
+	server := newTestServer(t)
+	server.Override("listPets", Response{Status: http.StatusOK, Body: []interface{}{}})
+	server.Override("GET /pets/{petId}", Response{Status: http.StatusNotFound, Headers: map[string]string{"X-Reason": "gone"}})
+
+	if rec := serve(server, "GET", "/v1/pets", ""); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("unexpected overridden response %v: %v", rec.Code, rec.Body.String())
+	}
+	if rec := serve(server, "GET", "/v1/pets/1", ""); rec.Code != http.StatusNotFound || rec.Header().Get("X-Reason") != "gone" {
+		t.Errorf("unexpected overridden response %v: %v", rec.Code, rec.Header())
+	}
+
+	server.Reset()
+	if rec := serve(server, "GET", "/v1/pets/1", ""); rec.Code != http.StatusOK {
+		t.Errorf("unexpected response after reset %v: %v", rec.Code, rec.Body.String())
+	}
+}
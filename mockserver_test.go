@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMockServerInvalidSpec(t *testing.T) {
+	specFileName := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := ioutil.WriteFile(specFileName, []byte(`
+openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Invalid
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: pet
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := newMockServer(specFileName, "")
+	if err == nil || !strings.Contains(err.Error(), "schema validation failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HasDefaults reports whether type generated for schema in context sets default values,
+// that is some of its properties have defaults or are objects with defaults themselves
+func (s Spec) HasDefaults(schema Schema, context string) bool {
+	return s.hasDefaults(schema, context, map[string]bool{})
+}
+
+// HasNestedDefaults reports whether property is struct or array of structs with defaults
+func (s Spec) HasNestedDefaults(property Schema, context string) bool {
+	return s.hasNestedDefaults(property, context, map[string]bool{})
+}
+
+func (s Spec) hasNestedDefaults(property Schema, context string, visited map[string]bool) bool {
+	if property.IsNullableValue() {
+		return false
+	}
+	if property.Type.IsArray() && property.Items != nil {
+		return s.hasNestedDefaults(*property.Items, context, visited)
+	}
+	return s.hasDefaults(property, context, visited)
+}
+
+func (s Spec) hasDefaults(schema Schema, context string, visited map[string]bool) bool {
+	if schema.Ref.IsSet() {
+		// referenced types are generated in their own context
+		context = s.getRefContext(schema.Ref, context)
+		key := context + string(schema.Ref)
+		if visited[key] {
+			return false
+		}
+		visited[key] = true
+	}
+
+	schema = s.ResolveSchema(schema)
+	for _, inner := range schema.AllOf {
+		// embedded allOf composition
+		if inner.Ref.IsSet() && s.hasDefaults(inner, context, visited) {
+			return true
+		}
+		if !inner.Ref.IsSet() && s.hasPropertiesDefaults(inner, context, visited) {
+			return true
+		}
+	}
+
+	return s.hasPropertiesDefaults(schema, context, visited)
+}
+
+func (s Spec) hasPropertiesDefaults(schema Schema, context string, visited map[string]bool) bool {
+	if !schema.Type.IsObject() || schema.IsMap() {
+		return false
+	}
+
+	for _, property := range schema.Properties {
+		if property.IsExcludedInContext(context) || property.IsFile() || property.IsNullableValue() {
+			continue
+		}
+		if (property.HasDefault() && !property.HasZeroDefault()) || s.hasNestedDefaults(property, context, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getRefContext returns context of type generated for referenced schema,
+// requests use request variants of components when they exist
+func (s Spec) getRefContext(ref Ref, context string) string {
+	isRequest := context == PropertiesContextRequestBody || context == PropertiesContextRequestComponents
+	if component, name := ref.GetFullName(); component == "schemas" && isRequest && s.HasRequestVariant(name) {
+		return PropertiesContextRequestComponents
+	}
+	return PropertiesContextComponents
+}
+
+// HasParametersDefaults reports whether parameters of operation have defaults
+func (s Spec) HasParametersDefaults(op Operation) bool {
+	for _, parameter := range op.Parameters {
+		if (parameter.Schema.HasDefault() && !parameter.Schema.HasZeroDefault()) ||
+			s.HasNestedDefaults(parameter.Schema, PropertiesContextParameters) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPointerParameter reports whether field of parameter is generated as pointer
+func (s Spec) IsPointerParameter(parameter Parameter) bool {
+	return !parameter.IsRequired() && !parameter.Schema.HasDefault() && !s.IsNillableSchema(parameter.Schema)
+}
+
+// IsPointerProperty reports whether field of property is generated as pointer in context
+func (s Spec) IsPointerProperty(parent Schema, name string, property Schema, context string) bool {
+	if parent.IsFieldOptional(name) {
+		if s.IsStruct(property) {
+			return true
+		}
+		if context == PropertiesContextParameters || context == PropertiesContextRequestBody {
+			return !property.HasDefault() && !s.IsOmmitableSchema(property)
+		}
+		return false
+	}
+	return s.IsRecursiveSchema(property)
+}
+
+// GetDefaultZeroCheck returns condition of field holding no value, so default is set to it
+func (s Spec) GetDefaultZeroCheck(field string, schema Schema, isPointer bool) string {
+	resolved := s.ResolveSchema(schema)
+	switch {
+	case isPointer, resolved.Type.IsArray(), resolved.IsMap(), resolved.Format == "binary":
+		return field + " == nil"
+	case resolved.Type == "string":
+		return field + ` == ""`
+	case resolved.Type == "integer", resolved.Type == "number":
+		return field + " == 0"
+	case resolved.Type == "boolean":
+		return "!" + field
+	}
+	return ""
+}
+
+// GetDefaultLiteral returns Go literal of default value: literal of scalars, body of composite literal
+// like {"a", "b"} for arrays and maps of scalars to be prefixed with type, and empty string
+// for defaults of objects which are set by unmarshalling JSON
+func (s Spec) GetDefaultLiteral(schema Schema) (string, error) {
+	resolved := s.ResolveSchema(schema)
+
+	switch {
+	case resolved.Type.IsArray():
+		items, ok := schema.Default.([]interface{})
+		if !ok {
+			return "", fmt.Errorf("default %v of array is not array", schema.Default)
+		}
+		if resolved.Items == nil || !s.isScalarSchema(*resolved.Items) {
+			return "", nil
+		}
+		literals := make([]string, 0, len(items))
+		for _, item := range items {
+			literal, err := s.getScalarLiteral(*resolved.Items, item)
+			if err != nil {
+				return "", err
+			}
+			literals = append(literals, literal)
+		}
+		return "{" + strings.Join(literals, ", ") + "}", nil
+	case resolved.IsMap():
+		values, ok := schema.Default.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("default %v of map is not object", schema.Default)
+		}
+		if !resolved.AdditionalProperties.IsTyped() || !s.isScalarSchema(resolved.AdditionalProperties.GetSchema()) {
+			return "", nil
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		literals := make([]string, 0, len(keys))
+		for _, key := range keys {
+			literal, err := s.getScalarLiteral(resolved.AdditionalProperties.GetSchema(), values[key])
+			if err != nil {
+				return "", err
+			}
+			literals = append(literals, strconv.Quote(key)+": "+literal)
+		}
+		return "{" + strings.Join(literals, ", ") + "}", nil
+	case s.isScalarSchema(resolved):
+		return s.getScalarLiteral(resolved, schema.Default)
+	}
+
+	return "", nil
+}
+
+// GetDefaultJSON returns default value encoded as JSON string literal
+func (s Spec) GetDefaultJSON(schema Schema) (string, error) {
+	data, err := json.Marshal(schema.Default)
+	if err != nil {
+		return "", fmt.Errorf("invalid default %v: %v", schema.Default, err)
+	}
+	return strconv.Quote(string(data)), nil
+}
+
+func (s Spec) isScalarSchema(schema Schema) bool {
+	schema = s.ResolveSchema(schema)
+	return schema.Type.IsPrimitive() && schema.Format != "binary" && !schema.IsNullableValue()
+}
+
+func (s Spec) getScalarLiteral(schema Schema, value interface{}) (string, error) {
+	schema = s.ResolveSchema(schema)
+
+	switch schema.Type {
+	case "string":
+		// unquoted scalars like 10 are strings as well
+		return strconv.Quote(fmt.Sprint(value)), nil
+	case "boolean":
+		if v, ok := value.(bool); ok {
+			return strconv.FormatBool(v), nil
+		}
+		return "", fmt.Errorf("default %v of boolean is not boolean", value)
+	}
+
+	var number float64
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		number = v
+	default:
+		return "", fmt.Errorf("default %v of %v is not number", value, schema.Type)
+	}
+
+	if schema.Type == "integer" {
+		if number != math.Trunc(number) {
+			return "", fmt.Errorf("default %v of integer is not integer", value)
+		}
+		return strconv.FormatInt(int64(number), 10), nil
+	}
+	return strconv.FormatFloat(number, 'g', -1, 64), nil
+}
@@ -0,0 +1,296 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is difference between two versions of spec
+type Change struct {
+	Location   string
+	Message    string
+	IsBreaking bool
+}
+
+func (c Change) String() string {
+	level := "non-breaking"
+	if c.IsBreaking {
+		level = "BREAKING"
+	}
+	return fmt.Sprintf("%-12s %v: %v", level, c.Location, c.Message)
+}
+
+type differ struct {
+	old     Spec
+	new     Spec
+	changes []Change
+	// comparing holds pairs of referenced schemas being compared, so recursive schemas are compared once
+	comparing map[string]bool
+}
+
+// Diff compares operations of two versions of spec and classifies changes as breaking
+// when clients built for old version may fail with new one
+func Diff(old Spec, new Spec) []Change {
+	d := differ{old: old, new: new, comparing: make(map[string]bool)}
+
+	for _, path := range sortedKeys(old.Paths) {
+		for _, method := range sortedKeys(old.Paths[path].Operations) {
+			location := strings.ToUpper(method) + " " + path
+			newOperation, ok := new.Paths[path].Operations[method]
+			if !ok {
+				d.add(location, "operation removed", true)
+				continue
+			}
+			d.diffOperation(location, old.Paths[path].Operations[method], newOperation)
+		}
+	}
+	for _, path := range sortedKeys(new.Paths) {
+		for _, method := range sortedKeys(new.Paths[path].Operations) {
+			if _, ok := old.Paths[path].Operations[method]; !ok {
+				d.add(strings.ToUpper(method)+" "+path, "operation added", false)
+			}
+		}
+	}
+
+	return d.changes
+}
+
+// HasBreakingChanges reports whether any of changes is breaking
+func HasBreakingChanges(changes []Change) bool {
+	for _, change := range changes {
+		if change.IsBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) add(location string, message string, isBreaking bool) {
+	d.changes = append(d.changes, Change{Location: location, Message: message, IsBreaking: isBreaking})
+}
+
+func (d *differ) diffOperation(location string, old Operation, new Operation) {
+	oldParameters := make(map[string]Parameter)
+	for _, parameter := range old.Parameters {
+		oldParameters[parameter.In+" "+parameter.Name] = parameter
+	}
+	newParameters := make(map[string]Parameter)
+	for _, parameter := range new.Parameters {
+		newParameters[parameter.In+" "+parameter.Name] = parameter
+	}
+
+	for _, key := range sortedKeys(oldParameters) {
+		parameterLocation := location + " parameter " + key
+		oldParameter := oldParameters[key]
+		newParameter, ok := newParameters[key]
+		if !ok {
+			d.add(parameterLocation, "parameter removed", true)
+			continue
+		}
+		if !oldParameter.IsRequired() && newParameter.IsRequired() {
+			d.add(parameterLocation, "parameter became required", true)
+		} else if oldParameter.IsRequired() && !newParameter.IsRequired() {
+			d.add(parameterLocation, "parameter became optional", false)
+		}
+		d.diffSchema(parameterLocation, "", oldParameter.Schema, newParameter.Schema, true)
+	}
+	for _, key := range sortedKeys(newParameters) {
+		if _, ok := oldParameters[key]; !ok {
+			if newParameters[key].IsRequired() {
+				d.add(location+" parameter "+key, "required parameter added", true)
+			} else {
+				d.add(location+" parameter "+key, "optional parameter added", false)
+			}
+		}
+	}
+
+	bodyLocation := location + " request body"
+	switch {
+	case old.HasRequestBody() && !new.HasRequestBody():
+		d.add(bodyLocation, "request body removed", true)
+	case !old.HasRequestBody() && new.HasRequestBody():
+		d.add(bodyLocation, "request body added", new.RequestBody.IsRequired)
+	case old.HasRequestBody():
+		if !old.RequestBody.IsRequired && new.RequestBody.IsRequired {
+			d.add(bodyLocation, "request body became required", true)
+		}
+		d.diffMediaTypes(bodyLocation, old.RequestBody.Content, new.RequestBody.Content)
+		d.diffSchema(bodyLocation, "", old.RequestBody.Content.GetBindableParametersSchema(), new.RequestBody.Content.GetBindableParametersSchema(), true)
+	}
+
+	for _, status := range sortedKeys(old.Responses) {
+		responseLocation := location + " response " + status
+		newResponse, ok := new.Responses[status]
+		if !ok {
+			d.add(responseLocation, "response removed", true)
+			continue
+		}
+		oldContent, newContent := d.responseContent(d.old, old.Responses[status]), d.responseContent(d.new, newResponse)
+		d.diffMediaTypes(responseLocation, oldContent, newContent)
+		d.diffSchema(responseLocation, "", oldContent.GetBindableParametersSchema(), newContent.GetBindableParametersSchema(), false)
+	}
+	for _, status := range sortedKeys(new.Responses) {
+		if _, ok := old.Responses[status]; !ok {
+			d.add(location+" response "+status, "response added", false)
+		}
+	}
+}
+
+// responseContent follows chain of response references, limited by number of components in case of cycle
+func (d *differ) responseContent(s Spec, response Response) Content {
+	for i := 0; response.Ref.IsSet() && i <= len(s.Components.Responses); i++ {
+		response = s.Components.Responses[response.Ref.GetName()]
+	}
+	return response.Content
+}
+
+// diffMediaTypes reports media types that are not accepted or sent anymore
+func (d *differ) diffMediaTypes(location string, old Content, new Content) {
+	for _, mediaType := range sortedKeys(old) {
+		if _, ok := new[MediaType(mediaType)]; !ok {
+			d.add(location, fmt.Sprintf("media type %v removed", mediaType), true)
+		}
+	}
+	for _, mediaType := range sortedKeys(new) {
+		if _, ok := old[MediaType(mediaType)]; !ok {
+			d.add(location, fmt.Sprintf("media type %v added", mediaType), false)
+		}
+	}
+}
+
+// diffSchema compares schemas of request (values sent by clients) or response (values received by clients),
+// path points to compared value inside of location
+func (d *differ) diffSchema(location string, path string, old Schema, new Schema, isRequest bool) {
+	if old.Ref.IsSet() && new.Ref.IsSet() {
+		key := fmt.Sprintf("%v %v", old.Ref, new.Ref)
+		if d.comparing[key] {
+			return
+		}
+		d.comparing[key] = true
+		defer delete(d.comparing, key)
+	}
+
+	valueLocation := location
+	if path != "" {
+		valueLocation = location + " " + path
+	}
+	old, new = d.old.ResolveSchema(old), d.new.ResolveSchema(new)
+
+	if oldType, newType := old.diffType(), new.diffType(); oldType != newType {
+		if old.Format == "" && oldType == string(new.Type) {
+			// format only details values of the same type
+			d.add(valueLocation, fmt.Sprintf("format %v added", new.Format), false)
+		} else {
+			d.add(valueLocation, fmt.Sprintf("type changed from %v to %v", oldType, newType), true)
+			return
+		}
+	}
+
+	d.diffEnum(valueLocation, old.Enum, new.Enum, isRequest)
+
+	if old.Items != nil && new.Items != nil {
+		d.diffSchema(location, path+"[]", *old.Items, *new.Items, isRequest)
+	}
+	if old.HasAdditionalProperties() && new.HasAdditionalProperties() && old.AdditionalProperties.IsTyped() && new.AdditionalProperties.IsTyped() {
+		d.diffSchema(location, path+"{}", old.AdditionalProperties.GetSchema(), new.AdditionalProperties.GetSchema(), isRequest)
+	}
+
+	for _, name := range sortedKeys(old.Properties) {
+		propertyPath := joinPath(path, name)
+		newProperty, ok := new.Properties[name]
+		if !ok {
+			// clients may rely on received fields, but sent fields are ignored
+			d.add(location+" "+propertyPath, "property removed", !isRequest)
+			continue
+		}
+		wasOptional, isOptional := old.IsFieldOptional(name), new.IsFieldOptional(name)
+		if wasOptional && !isOptional {
+			d.add(location+" "+propertyPath, "property became required", isRequest)
+		} else if !wasOptional && isOptional {
+			d.add(location+" "+propertyPath, "property became optional", !isRequest)
+		}
+		d.diffSchema(location, propertyPath, old.Properties[name], newProperty, isRequest)
+	}
+	for _, name := range sortedKeys(new.Properties) {
+		if _, ok := old.Properties[name]; !ok {
+			if isRequest && !new.IsFieldOptional(name) {
+				d.add(location+" "+joinPath(path, name), "required property added", true)
+			} else {
+				d.add(location+" "+joinPath(path, name), "property added", false)
+			}
+		}
+	}
+}
+
+// diffEnum reports narrowed enums of requests and widened enums of responses as breaking
+func (d *differ) diffEnum(location string, old []string, new []string, isRequest bool) {
+	if len(old) == 0 && len(new) == 0 {
+		return
+	}
+	if len(old) > 0 && len(new) == 0 {
+		d.add(location, "enum restriction removed", !isRequest)
+		return
+	}
+	if len(old) == 0 {
+		d.add(location, "enum restriction added", isRequest)
+		return
+	}
+
+	removed, added := subtract(old, new), subtract(new, old)
+	if len(removed) > 0 {
+		d.add(location, fmt.Sprintf("enum values removed: %v", strings.Join(removed, ", ")), isRequest)
+	}
+	if len(added) > 0 {
+		d.add(location, fmt.Sprintf("enum values added: %v", strings.Join(added, ", ")), !isRequest)
+	}
+}
+
+// diffType describes type of schema for comparison, referenced schemas are compared by content
+func (s Schema) diffType() string {
+	switch {
+	case len(s.AnyOf) > 0:
+		return "anyOf"
+	case s.IsMap():
+		return "map"
+	case s.Type.IsArray():
+		return "array"
+	case s.Type.IsObject():
+		return "object"
+	case s.Format != "":
+		return string(s.Type) + "(" + s.Format + ")"
+	}
+	return string(s.Type)
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func subtract(values []string, other []string) []string {
+	otherMap := make(map[string]bool)
+	for _, value := range other {
+		otherMap[value] = true
+	}
+	var result []string
+	for _, value := range values {
+		if !otherMap[value] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// sortedKeys returns sorted keys of map with string keys, so changes are reported in stable order
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
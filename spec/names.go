@@ -0,0 +1,331 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+const componentsSchemasPrefix = "#/components/schemas/"
+
+// InlineType is named type generated for inline object declared inside of other schema
+type InlineType struct {
+	Name    string
+	Context string
+	Schema  Schema
+}
+
+// isInlineStruct reports whether schema is declared in place and is generated as struct
+func (s Schema) isInlineStruct() bool {
+	if s.Ref.IsSet() || len(s.AnyOf) > 0 {
+		return false
+	}
+	return len(s.AllOf) > 0 || (s.Type.IsObject() && !s.IsMap())
+}
+
+// walkInContext calls cb for schema and all of its inner schemas generated in given context
+func (s Schema) walkInContext(context string, cb func(Schema)) {
+	cb(s)
+	for _, inner := range s.AllOf {
+		inner.walkInContext(context, cb)
+	}
+	if s.Items != nil {
+		s.Items.walkInContext(context, cb)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		s.AdditionalProperties.Schema.walkInContext(context, cb)
+	}
+	for _, property := range s.Properties {
+		if !property.IsExcludedInContext(context) {
+			property.walkInContext(context, cb)
+		}
+	}
+}
+
+// AssignInlineTypeNames marks every inline object nested in spec declarations with
+// declaration it belongs to and path inside of it, so named types are generated for them:
+// properties are named after parent type and property name, array items get Item suffix
+// and additional properties values get Value suffix
+func (s *Spec) AssignInlineTypeNames() {
+	for name, schema := range s.Components.Schemas {
+		assignInlineTypeNames(&schema, componentsSchemasPrefix+name, "", false)
+		s.Components.Schemas[name] = schema
+	}
+	for name, response := range s.Components.Responses {
+		assignContentInlineTypeNames(response.Content, name+"Response", "", false)
+	}
+	for path, item := range s.Paths {
+		for method, operation := range item.Operations {
+			baseName := OperationId(path, method, operation)
+			for i := range operation.Parameters {
+				parameter := &operation.Parameters[i]
+				assignInlineTypeNames(&parameter.Schema, baseName+"Params", strcase.ToCamel(parameter.Name), true)
+			}
+			assignContentInlineTypeNames(operation.RequestBody.Content, baseName+"Body", "", false)
+			for status, response := range operation.Responses {
+				responseName := baseName + "Http" + strcase.ToCamel(status)
+				if response.IsEventStream() {
+					assignContentInlineTypeNames(response.Content, responseName+"Event", "Data", true)
+				} else {
+					assignContentInlineTypeNames(response.Content, responseName+"Response", "", false)
+				}
+			}
+		}
+	}
+}
+
+func assignContentInlineTypeNames(content Content, root string, suffix string, isNested bool) {
+	for mediaType, mediaTypeObject := range content {
+		assignInlineTypeNames(&mediaTypeObject.Schema, root, suffix, isNested)
+		content[mediaType] = mediaTypeObject
+	}
+}
+
+func assignInlineTypeNames(schema *Schema, root string, suffix string, isNested bool) {
+	if isNested && schema.isInlineStruct() {
+		schema.InlineRoot, schema.InlineSuffix = root, suffix
+	}
+	for i := range schema.AllOf {
+		assignInlineTypeNames(&schema.AllOf[i], root, suffix, false)
+	}
+	if schema.Items != nil {
+		assignInlineTypeNames(schema.Items, root, suffix+"Item", true)
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		assignInlineTypeNames(schema.AdditionalProperties.Schema, root, suffix+"Value", true)
+	}
+	for name, property := range schema.Properties {
+		assignInlineTypeNames(&property, root, suffix+strcase.ToCamel(name), true)
+		schema.Properties[name] = property
+	}
+}
+
+// IsInlineType reports whether named type is generated for schema
+func (s Schema) IsInlineType() bool {
+	return s.InlineRoot != ""
+}
+
+// GetInlineTypeName returns name of type generated for inline object in given context.
+// Objects nested in components with request variant are named after the variant in requests
+func (s Spec) GetInlineTypeName(schema Schema, context string) string {
+	isRequest := context == PropertiesContextRequestBody || context == PropertiesContextRequestComponents
+
+	root := schema.InlineRoot
+	hasVariant := false
+	if strings.HasPrefix(root, componentsSchemasPrefix) {
+		name := strings.TrimPrefix(root, componentsSchemasPrefix)
+		hasVariant = isRequest && s.HasRequestVariant(name)
+		root = name + "Schema"
+		if hasVariant {
+			root = name + "RequestSchema"
+		}
+	}
+
+	if schema.XGoName != "" {
+		if hasVariant {
+			return schema.XGoName + "Request"
+		}
+		return schema.XGoName
+	}
+
+	return root + schema.InlineSuffix
+}
+
+// GetInlineTypes returns named types of all inline objects with contexts they are generated in
+func (s Spec) GetInlineTypes() []InlineType {
+	typesMap := make(map[string]InlineType)
+	collect := func(schema Schema, context string) {
+		schema.walkInContext(context, func(inner Schema) {
+			if !inner.IsInlineType() {
+				return
+			}
+			name := s.GetInlineTypeName(inner, context)
+			// declaration itself is generated as struct, nested objects keep their names
+			inner.InlineRoot, inner.InlineSuffix, inner.XGoName = "", "", ""
+			typesMap[name] = InlineType{Name: name, Context: context, Schema: inner}
+		})
+	}
+
+	for name, schema := range s.Components.Schemas {
+		collect(schema, PropertiesContextComponents)
+		if s.HasRequestVariant(name) {
+			collect(schema, PropertiesContextRequestComponents)
+		}
+	}
+	for _, response := range s.Components.Responses {
+		collect(response.Content.GetBindableParametersSchema(), PropertiesContextComponents)
+	}
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			for _, parameter := range operation.Parameters {
+				collect(parameter.Schema, PropertiesContextParameters)
+			}
+			collect(operation.RequestBody.Content.GetBindableParametersSchema(), PropertiesContextRequestBody)
+			for _, response := range operation.Responses {
+				collect(response.Content.GetBindableParametersSchema(), PropertiesContextComponents)
+			}
+		}
+	}
+
+	var types []InlineType
+	for _, t := range typesMap {
+		types = append(types, t)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+
+	return types
+}
+
+// getDeclaredTypeNames returns names of types generated for spec declarations with their origins
+func (s Spec) getDeclaredTypeNames() map[string]string {
+	names := map[string]string{"Controller": "controller interface"}
+
+	for name := range s.Components.Schemas {
+		names[name+"Schema"] = componentsSchemasPrefix + name
+		if s.HasRequestVariant(name) {
+			names[name+"RequestSchema"] = componentsSchemasPrefix + name + " request variant"
+		}
+	}
+	for name := range s.Components.Responses {
+		names[name+"Response"] = "#/components/responses/" + name
+	}
+	for _, t := range s.GetNullableTypes() {
+		names[t.Name] = "nullable " + t.GoType
+	}
+	for path, item := range s.Paths {
+		for method, operation := range item.Operations {
+			baseName := OperationId(path, method, operation)
+			origin := strings.ToUpper(method) + " " + path
+			if len(operation.Parameters) > 0 {
+				names[baseName+"Params"] = origin + " parameters"
+			}
+			if operation.HasRequestBodyBindableParameters() {
+				names[baseName+"Body"] = origin + " request body"
+			}
+			if !operation.IsAllEmptyResponses() {
+				names[baseName+"Response"] = origin + " responses"
+			}
+			for status, response := range operation.Responses {
+				responseName := baseName + "Http" + strcase.ToCamel(status)
+				if response.IsInlineStructType() && !response.IsEmpty() {
+					names[responseName+"Response"] = origin + " response " + status
+				}
+				if response.IsEventStream() {
+					names[responseName+"Event"] = origin + " response " + status + " event"
+					names[responseName+"Stream"] = origin + " response " + status + " stream"
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+// ValidateTypeNames checks that names of inline object types do not collide
+// with each other and with other generated types
+func (s Spec) ValidateTypeNames() error {
+	declared := s.getDeclaredTypeNames()
+	inlineOrigins := make(map[string]string)
+
+	var errs []string
+	check := func(schema Schema, context string, origin string) {
+		schema.walkInContext(context, func(inner Schema) {
+			if !inner.IsInlineType() {
+				return
+			}
+			name := s.GetInlineTypeName(inner, context)
+			if other, ok := declared[name]; ok {
+				errs = append(errs, fmt.Sprintf("inline object type %v in %v collides with %v", name, origin, other))
+			} else if other, ok := inlineOrigins[name]; ok && other != origin {
+				errs = append(errs, fmt.Sprintf("inline object type %v in %v collides with inline object in %v", name, origin, other))
+			} else {
+				inlineOrigins[name] = origin
+			}
+		})
+	}
+
+	// media types of content share one generated type, so inline objects named the same by them
+	// should be declared the same way
+	checkContent := func(content Content, context string, origin string) {
+		check(content.GetBindableParametersSchema(), context, origin)
+
+		declarations := make(map[string]Schema)
+		mediaTypes := make(map[string]MediaType)
+		for _, mediaType := range content.GetMediaTypes() {
+			content[mediaType].Schema.walkInContext(context, func(inner Schema) {
+				if !inner.IsInlineType() {
+					return
+				}
+				name := s.GetInlineTypeName(inner, context)
+				if other, ok := declarations[name]; !ok {
+					declarations[name], mediaTypes[name] = inner, mediaType
+				} else if !reflect.DeepEqual(other, inner) {
+					errs = append(errs, fmt.Sprintf("inline object type %v in %v is declared differently by media types %v and %v",
+						name, origin, mediaTypes[name], mediaType))
+				}
+			})
+		}
+	}
+
+	for name, schema := range s.Components.Schemas {
+		check(schema, PropertiesContextComponents, componentsSchemasPrefix+name)
+		if s.HasRequestVariant(name) {
+			check(schema, PropertiesContextRequestComponents, componentsSchemasPrefix+name)
+		}
+	}
+	for name, response := range s.Components.Responses {
+		checkContent(response.Content, PropertiesContextComponents, "#/components/responses/"+name)
+	}
+	for path, item := range s.Paths {
+		for method, operation := range item.Operations {
+			origin := strings.ToUpper(method) + " " + path
+			for _, parameter := range operation.Parameters {
+				check(parameter.Schema, PropertiesContextParameters, origin+" parameters")
+			}
+			checkContent(operation.RequestBody.Content, PropertiesContextRequestBody, origin+" request body")
+			for status, response := range operation.Responses {
+				checkContent(response.Content, PropertiesContextComponents, origin+" response "+status)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("%v", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// ValidateOperationNames checks that operations get distinct names in generated code,
+// names are derived from x-go-name, operationId or method and path
+func (s Spec) ValidateOperationNames() error {
+	origins := make(map[string][]string)
+	for path, item := range s.Paths {
+		for method, operation := range item.Operations {
+			name := OperationId(path, method, operation)
+			origins[name] = append(origins[name], strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	var errs []string
+	for name, operations := range origins {
+		if len(operations) > 1 {
+			sort.Strings(operations)
+			errs = append(errs, fmt.Sprintf("operation name %v is shared by %v", name, strings.Join(operations, ", ")))
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("%v, set distinct operationId or x-go-name", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"fmt"
 	"github.com/Masterminds/semver"
 	"github.com/iancoleman/strcase"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 const PropertiesContextComponents string = "components"
 const PropertiesContextParameters string = "parameters"
 const PropertiesContextRequestBody string = "requestBody"
+const PropertiesContextRequestComponents string = "requestComponents"
 
 type Ref string
 
@@ -84,6 +86,8 @@ type Parameter struct {
 	Name        string `yaml:"name"`
 	Required    bool   `yaml:"required"`
 	Description string `yaml:"description"`
+	Style       string `yaml:"style"`
+	Explode     *bool  `yaml:"explode"`
 	Schema      Schema `yaml:"schema"`
 }
 
@@ -91,30 +95,158 @@ func (p Parameter) IsRequired() bool {
 	return p.Required || p.In == "path"
 }
 
+// GetDefaultStyle returns serialization style of parameters in location of parameter:
+// simple for path and header parameters, form for query and cookie ones
+func (p Parameter) GetDefaultStyle() string {
+	if p.In == "path" || p.In == "header" {
+		return "simple"
+	}
+	return "form"
+}
+
+// GetStyle returns serialization style of parameter
+func (p Parameter) GetStyle() string {
+	if p.Style == "" {
+		return p.GetDefaultStyle()
+	}
+	return p.Style
+}
+
+// IsExplode reports whether array items and object properties are serialized as separate parameters,
+// by default only form style is exploded
+func (p Parameter) IsExplode() bool {
+	if p.Explode == nil {
+		return p.GetStyle() == "form"
+	}
+	return *p.Explode
+}
+
 type Schema struct {
 	AllOf []Schema `yaml:"allOf"`
 	AnyOf []Schema `yaml:"anyOf"`
 
-	Ref                  Ref               `yaml:"$ref"`
-	Description          string            `yaml:"description"`
-	Type                 Type              `yaml:"type"`
-	Format               string            `yaml:"format"`
-	Minimum              *int              `yaml:"minimum"`
-	Maximum              *int              `yaml:"maximum"`
-	MinimumLength        *int              `yaml:"minLength"`
-	MaximumLength        *int              `yaml:"maxLength"`
-	Default              *string           `yaml:"default"`
-	Required             []string          `yaml:"required"`
-	Enum                 []string          `yaml:"enum"`
-	Items                *Schema           `yaml:"items"`
-	Properties           map[string]Schema `yaml:"properties"`
-	AdditionalProperties interface{}       `yaml:"additionalProperties"`
+	// XGoAllOfEmbed keeps allOf schemas as struct embedding referenced types instead of flattening
+	XGoAllOfEmbed bool `yaml:"x-go-allof-embed"`
+
+	// XGoName overrides name of type generated for inline object
+	XGoName string `yaml:"x-go-name"`
+
+	Ref                  Ref                   `yaml:"$ref"`
+	Description          string                `yaml:"description"`
+	Type                 Type                  `yaml:"type"`
+	Format               string                `yaml:"format"`
+	Minimum              *int                  `yaml:"minimum"`
+	Maximum              *int                  `yaml:"maximum"`
+	ExclusiveMinimum     bool                  `yaml:"exclusiveMinimum"`
+	ExclusiveMaximum     bool                  `yaml:"exclusiveMaximum"`
+	MinimumLength        *int                  `yaml:"minLength"`
+	MaximumLength        *int                  `yaml:"maxLength"`
+	MinimumItems         *int                  `yaml:"minItems"`
+	MaximumItems         *int                  `yaml:"maxItems"`
+	UniqueItems          bool                  `yaml:"uniqueItems"`
+	Default              interface{}           `yaml:"default"`
+	Nullable             bool                  `yaml:"nullable"`
+	ReadOnly             bool                  `yaml:"readOnly"`
+	WriteOnly            bool                  `yaml:"writeOnly"`
+	Required             []string              `yaml:"required"`
+	Enum                 []string              `yaml:"enum"`
+	Example              interface{}           `yaml:"example"`
+	Examples             []interface{}         `yaml:"examples"`
+	Items                *Schema               `yaml:"items"`
+	Properties           map[string]Schema     `yaml:"properties"`
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+
+	// PropertyNames keeps order of properties declaration
+	PropertyNames []string `yaml:"-"`
+
+	// EncodingContentType holds allowed content types of multipart body part
+	// taken from media type encoding object
+	EncodingContentType string `yaml:"-"`
+
+	// InlineRoot and InlineSuffix locate inline object inside of declaration
+	// it belongs to, they are assigned by Spec.AssignInlineTypeNames
+	InlineRoot   string `yaml:"-"`
+	InlineSuffix string `yaml:"-"`
+}
+
+// AdditionalProperties is either boolean flag or schema of additional properties values
+type AdditionalProperties struct {
+	IsAllowed bool
+	Schema    *Schema
+}
+
+func (a *AdditionalProperties) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var isAllowed bool
+	if err := unmarshal(&isAllowed); err == nil {
+		a.IsAllowed = isAllowed
+		return nil
+	}
+
+	schema := Schema{}
+	if err := unmarshal(&schema); err != nil {
+		return err
+	}
+	a.IsAllowed = true
+	a.Schema = &schema
+
+	return nil
+}
+
+// IsTyped reports whether additional properties values have declared schema
+func (a AdditionalProperties) IsTyped() bool {
+	return a.Schema != nil && a.Schema.IsSet()
+}
+
+func (a AdditionalProperties) GetSchema() Schema {
+	if a.Schema == nil {
+		return Schema{}
+	}
+	return *a.Schema
+}
+
+func (s Schema) HasAdditionalProperties() bool {
+	return s.AdditionalProperties != nil && s.AdditionalProperties.IsAllowed
+}
+
+// IsMap reports whether schema is generated as map type
+func (s Schema) IsMap() bool {
+	return s.HasAdditionalProperties() && len(s.Properties) == 0
+}
+
+// HasExtraProperties reports whether schema is generated as struct
+// with AdditionalProperties map for undeclared properties
+func (s Schema) HasExtraProperties() bool {
+	return s.HasAdditionalProperties() && len(s.Properties) > 0
 }
 
 func (s Schema) HasDefault() bool {
 	return s.Default != nil
 }
 
+// HasZeroDefault reports whether default value is the same as zero value of generated type,
+// such defaults are not set explicitly
+func (s Schema) HasZeroDefault() bool {
+	switch value := s.Default.(type) {
+	case string:
+		return value == ""
+	case bool:
+		return !value
+	case int:
+		return value == 0
+	case int64:
+		return value == 0
+	case uint64:
+		return value == 0
+	case float64:
+		return value == 0
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
 func (s Schema) IsFieldOptional(fieldName string) bool {
 	for _, r := range s.Required {
 		if fieldName == r {
@@ -124,6 +256,86 @@ func (s Schema) IsFieldOptional(fieldName string) bool {
 	return true
 }
 
+// IsNullableValue reports whether schema is generated as Nullable type
+// that distinguishes absent, null and set values
+func (s Schema) IsNullableValue() bool {
+	return s.Nullable && (s.Ref.IsSet() || (s.Type.IsPrimitive() && !s.IsBinary()))
+}
+
+// IsExcludedInContext reports whether property is not generated in given context:
+// read only properties are not accepted in requests and write only are not sent in responses
+func (s Schema) IsExcludedInContext(context string) bool {
+	if s.ReadOnly {
+		return context == PropertiesContextRequestBody || context == PropertiesContextRequestComponents
+	}
+	if s.WriteOnly {
+		return context == PropertiesContextComponents
+	}
+	return false
+}
+
+// walk calls cb for schema and all of its inner schemas without following references
+func (s Schema) walk(cb func(Schema)) {
+	cb(s)
+	for _, inner := range s.AllOf {
+		inner.walk(cb)
+	}
+	for _, inner := range s.AnyOf {
+		inner.walk(cb)
+	}
+	if s.Items != nil {
+		s.Items.walk(cb)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		s.AdditionalProperties.Schema.walk(cb)
+	}
+	for _, property := range s.Properties {
+		property.walk(cb)
+	}
+}
+
+// IsFlattenedAllOf reports whether schema is allOf composition generated as single struct
+func (s Schema) IsFlattenedAllOf() bool {
+	return len(s.AllOf) > 0 && !s.XGoAllOfEmbed
+}
+
+// typeSignature describes Go type generated for schema, it is used to detect
+// properties declared with different types
+func (s Schema) typeSignature() string {
+	switch {
+	case s.Ref.IsSet():
+		return s.Ref.GetTypeName()
+	case len(s.AllOf) > 0:
+		return "allOf"
+	case len(s.AnyOf) > 0:
+		return "interface{}"
+	case s.IsMap():
+		if s.AdditionalProperties.IsTyped() {
+			return "map[string]" + s.AdditionalProperties.GetSchema().typeSignature()
+		}
+		return "map[string]interface{}"
+	case s.Type.IsArray() && s.Items != nil:
+		return "[]" + s.Items.typeSignature()
+	case s.Type.IsObject():
+		var names []string
+		for name, property := range s.Properties {
+			names = append(names, name+" "+property.typeSignature())
+		}
+		sort.Strings(names)
+		return "struct{" + strings.Join(names, "; ") + "}"
+	}
+	return s.GetGoType()
+}
+
+func (s Schema) IsBinary() bool {
+	return s.Type == "string" && s.Format == "binary"
+}
+
+// IsFile reports whether schema describes uploaded file or list of files
+func (s Schema) IsFile() bool {
+	return s.IsBinary() || (s.Type.IsArray() && s.Items != nil && s.Items.IsBinary())
+}
+
 func (s Schema) IsSet() bool {
 	return s.Ref.IsSet() || len(s.AllOf) > 0 || len(s.AnyOf) > 0 || s.Type != ""
 }
@@ -182,6 +394,8 @@ func (s Schema) GetGoType() string {
 			b.WriteString("float32")
 		case "double":
 			b.WriteString("float64")
+		case "binary":
+			b.WriteString("[]byte")
 		default:
 			b.WriteString(s.Type.GetTypeName())
 		}
@@ -199,11 +413,11 @@ type Response struct {
 func (r Response) IsInlineStructType() bool {
 	contentJSONSchema := r.Content.GetBindableParametersSchema()
 
-	if r.Ref.IsSet() || contentJSONSchema.Ref.IsSet() {
+	if r.Ref.IsSet() || contentJSONSchema.Ref.IsSet() || r.IsEventStream() {
 		return false
 	}
 
-	if contentJSONSchema.Type.IsObject() && contentJSONSchema.AdditionalProperties == nil {
+	if contentJSONSchema.Type.IsObject() && !contentJSONSchema.IsMap() {
 		return true
 	}
 
@@ -214,22 +428,122 @@ func (r Response) IsEmpty() bool {
 	return !r.Ref.IsSet() && !r.Content.GetBindableParametersSchema().Ref.IsSet() && r.Content.GetBindableParametersSchema().Type == ""
 }
 
-type Content map[string]struct {
-	Schema Schema `yaml:"schema"`
+// IsStream reports whether response body is written from io.Reader
+func (r Response) IsStream() bool {
+	return !r.Ref.IsSet() && !r.IsEmpty() && r.Content.GetMediaType().IsBinary()
+}
+
+// IsEventStream reports whether response is stream of server-sent events
+func (r Response) IsEventStream() bool {
+	return !r.Ref.IsSet() && r.Content.GetMediaType().IsEventStream() && r.Content.GetBindableParametersSchema().IsSet()
+}
+
+type MediaType string
+
+func (m MediaType) IsJSON() bool {
+	return m == "application/json" || strings.HasSuffix(string(m), "+json")
+}
+func (m MediaType) IsXML() bool {
+	return m == "application/xml" || m == "text/xml" || strings.HasSuffix(string(m), "+xml")
+}
+func (m MediaType) IsForm() bool {
+	return m == "application/x-www-form-urlencoded" || m == "multipart/form-data"
+}
+func (m MediaType) IsText() bool {
+	return m == "text/plain" || m == "text/csv"
+}
+func (m MediaType) IsBinary() bool {
+	return m == "application/octet-stream"
+}
+func (m MediaType) IsEventStream() bool {
+	return m == "text/event-stream"
+}
+func (m MediaType) IsSupported() bool {
+	return m.IsJSON() || m.IsXML() || m.IsForm() || m.IsText() || m.IsBinary() || m.IsEventStream()
+}
+
+// priority defines which of the supported media types is used
+// for generated types when content declares several of them
+func (m MediaType) priority() int {
+	switch {
+	case m == "application/json":
+		return 0
+	case m.IsJSON():
+		return 1
+	case m == "multipart/form-data":
+		return 2
+	case m.IsForm():
+		return 3
+	case m.IsXML():
+		return 4
+	case m.IsText():
+		return 5
+	case m.IsBinary():
+		return 6
+	case m.IsEventStream():
+		return 7
+	}
+	return 8
+}
+
+type Encoding struct {
+	ContentType string `yaml:"contentType"`
+}
+
+type Content map[MediaType]struct {
+	Schema   Schema              `yaml:"schema"`
+	Encoding map[string]Encoding `yaml:"encoding"`
+}
+
+func (c Content) GetMediaTypes() []MediaType {
+	var mediaTypes []MediaType
+	for t := range c {
+		if t.IsSupported() {
+			mediaTypes = append(mediaTypes, t)
+		}
+	}
+	sort.Slice(mediaTypes, func(i, j int) bool {
+		pi, pj := mediaTypes[i].priority(), mediaTypes[j].priority()
+		if pi != pj {
+			return pi < pj
+		}
+		return mediaTypes[i] < mediaTypes[j]
+	})
+	return mediaTypes
+}
+
+func (c Content) GetMediaType() MediaType {
+	mediaTypes := c.GetMediaTypes()
+	if len(mediaTypes) == 0 {
+		return ""
+	}
+	return mediaTypes[0]
 }
 
 func (c Content) GetBindableParametersSchema() Schema {
-	for t, s := range c {
-		if t == "application/json" || t == "multipart/form-data" {
-			return s.Schema
+	if t := c.GetMediaType(); t != "" {
+		schema := c[t].Schema
+		if len(c[t].Encoding) > 0 {
+			// properties are copied to not modify parsed spec
+			properties := make(map[string]Schema, len(schema.Properties))
+			for name, property := range schema.Properties {
+				property.EncodingContentType = c[t].Encoding[name].ContentType
+				properties[name] = property
+			}
+			schema.Properties = properties
 		}
+		return schema
 	}
 	return Schema{}
 }
 
 func (c Content) IsParametrizedContent() bool {
+	return c.GetMediaType() != ""
+}
+
+func (c Content) HasXMLMediaType() bool {
 	for t := range c {
-		if t == "multipart/form-data" || t == "application/json" {
+		if t.IsXML() {
 			return true
 		}
 	}
@@ -249,6 +563,11 @@ type Operation struct {
 	Responses    map[string]Response  `yaml:"responses"`
 	RequestBody  OperationRequestBody `yaml:"requestBody"`
 	XMiddlewares []string             `yaml:"x-middlewares"`
+	// XGoName overrides name of operation in generated code
+	XGoName string `yaml:"x-go-name"`
+	// Security overrides spec security requirements, empty list makes operation public
+	Security *[]SecurityRequirement `yaml:"security"`
+	Servers  []Server               `yaml:"servers"`
 }
 
 func (op Operation) HasRequestBodyBindableParameters() bool {
@@ -281,33 +600,22 @@ func (op Operation) IsAllEmptyResponses() bool {
 	return true
 }
 
-func (op Operation) GetResponses() map[string]Response {
-	responses := make(map[string]Response)
-	for status, response := range op.Responses {
-		if _, err := strconv.Atoi(status); err != nil {
-			continue
-		}
-		if !response.Ref.IsSet() && !response.Content.GetBindableParametersSchema().IsSet() {
-			continue
-		}
-		responses[status] = response
-	}
-	return responses
-}
-
 type Components struct {
-	Schemas   map[string]Schema   `yaml:"schemas"`
-	Responses map[string]Response `yaml:"responses"`
+	Schemas         map[string]Schema         `yaml:"schemas"`
+	Responses       map[string]Response       `yaml:"responses"`
+	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes"`
 }
 
-type PathOperation map[string]Operation
-
 type Spec struct {
-	Swagger    string                   `yaml:"swagger"`
-	Info       Info                     `yaml:"info"`
-	BasePath   string                   `yaml:"basePath"`
-	Paths      map[string]PathOperation `yaml:"paths"`
-	Components Components               `yaml:"components"`
+	Swagger    string                `yaml:"swagger"`
+	Info       Info                  `yaml:"info"`
+	Servers    []Server              `yaml:"servers"`
+	Paths      map[string]PathItem   `yaml:"paths"`
+	Components Components            `yaml:"components"`
+	Security   []SecurityRequirement `yaml:"security"`
+
+	// PathNames keeps order of paths declaration
+	PathNames []string `yaml:"-"`
 }
 
 func (s Spec) GetPackageName() string {
@@ -320,6 +628,7 @@ func (s Spec) GetPackageName() string {
 }
 
 func (s Spec) traverseSchema(schema Schema, cb func(Schema) bool) bool {
+	visited := make(map[string]bool)
 	var traverse func(schema Schema) bool
 	traverse = func(schema Schema) bool {
 		if cb(schema) == true {
@@ -327,7 +636,13 @@ func (s Spec) traverseSchema(schema Schema, cb func(Schema) bool) bool {
 		}
 
 		if schema.Ref.IsSet() {
-			if refSchema, ok := s.Components.Schemas[schema.Ref.GetName()]; ok {
+			name := schema.Ref.GetName()
+			if visited[name] {
+				// references cycle
+				return false
+			}
+			visited[name] = true
+			if refSchema, ok := s.Components.Schemas[name]; ok {
 				return traverse(refSchema)
 			}
 		}
@@ -337,13 +652,69 @@ func (s Spec) traverseSchema(schema Schema, cb func(Schema) bool) bool {
 	return traverse(schema)
 }
 
+// IsRecursiveSchema reports whether schema references struct type that contains itself
+// by value (directly or through other types), such fields are generated as pointers
+func (s Spec) IsRecursiveSchema(schema Schema) bool {
+	if !schema.Ref.IsSet() {
+		return false
+	}
+	component, name := schema.Ref.GetFullName()
+	if component != "schemas" {
+		return false
+	}
+
+	visited := make(map[string]bool)
+	var reaches func(current string) bool
+	reaches = func(current string) bool {
+		for _, next := range s.getValueReferences(s.Components.Schemas[current]) {
+			if next == name {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				if reaches(next) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return reaches(name)
+}
+
+// getValueReferences returns names of component schemas that are contained by value
+// in type generated for schema: referenced types, embedded or merged allOf schemas
+// and required struct properties, but not slices, maps, pointers or nullable values
+func (s Spec) getValueReferences(schema Schema) []string {
+	if schema.Ref.IsSet() {
+		if component, name := schema.Ref.GetFullName(); component == "schemas" {
+			return []string{name}
+		}
+		return nil
+	}
+
+	var refs []string
+	for _, inner := range schema.AllOf {
+		refs = append(refs, s.getValueReferences(inner)...)
+	}
+	for name, property := range schema.Properties {
+		if schema.IsFieldOptional(name) || property.IsNullableValue() || !s.IsStruct(property) {
+			continue
+		}
+		refs = append(refs, s.getValueReferences(property)...)
+	}
+
+	return refs
+}
+
 func (s Spec) IsNillableSchema(schema Schema) bool {
 	return s.traverseSchema(schema, func(schema Schema) bool {
-		if schema.AdditionalProperties != nil {
+		if schema.IsMap() {
 			// will be generated map type
 			return true
 		}
-		if schema.Type.IsArray() {
+		if schema.Type.IsArray() || schema.Format == "binary" {
 			// will be generated slice type
 			return true
 		}
@@ -353,7 +724,7 @@ func (s Spec) IsNillableSchema(schema Schema) bool {
 
 func (s Spec) IsStruct(schema Schema) bool {
 	return s.traverseSchema(schema, func(schema Schema) bool {
-		if schema.Type.IsObject() && schema.AdditionalProperties == nil {
+		if (schema.Type.IsObject() && !schema.IsMap()) || len(schema.AllOf) > 0 {
 			return true
 		}
 		return false
@@ -362,7 +733,7 @@ func (s Spec) IsStruct(schema Schema) bool {
 
 func (s Spec) IsOmmitableSchema(schema Schema) bool {
 	return s.traverseSchema(schema, func(schema Schema) bool {
-		if schema.AdditionalProperties != nil {
+		if schema.IsMap() {
 			// will be generated map type
 			return true
 		}
@@ -375,18 +746,28 @@ func (s Spec) IsOmmitableSchema(schema Schema) bool {
 }
 
 func (s Spec) GetUnderlyingSchema(ref Ref) Schema {
+	return s.getUnderlyingSchema(ref, map[Ref]bool{})
+}
+
+func (s Spec) getUnderlyingSchema(ref Ref, visited map[Ref]bool) Schema {
+	if visited[ref] {
+		// references cycle without any declared schema
+		return Schema{}
+	}
+	visited[ref] = true
+
 	component, name := ref.GetFullName()
 	if component == "schemas" {
 		schema := s.Components.Schemas[name]
 		if schema.Ref.IsSet() {
-			return s.GetUnderlyingSchema(schema.Ref)
+			return s.getUnderlyingSchema(schema.Ref, visited)
 		} else {
 			return schema
 		}
 	} else if component == "responses" {
 		response := s.Components.Responses[name]
 		if response.Ref.IsSet() {
-			return s.GetUnderlyingSchema(response.Ref)
+			return s.getUnderlyingSchema(response.Ref, visited)
 		} else {
 			return response.Content.GetBindableParametersSchema()
 		}
@@ -395,6 +776,472 @@ func (s Spec) GetUnderlyingSchema(ref Ref) Schema {
 	return Schema{}
 }
 
+func (s Spec) HasXMLContent() bool {
+	for _, response := range s.Components.Responses {
+		if response.Content.HasXMLMediaType() {
+			return true
+		}
+	}
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			if operation.RequestBody.Content.HasXMLMediaType() {
+				return true
+			}
+			for _, response := range operation.Responses {
+				if response.Content.HasXMLMediaType() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// GetResponseMediaTypes returns supported media types that response may be encoded with,
+// following reference to components responses
+func (s Spec) GetResponseMediaTypes(response Response) []MediaType {
+	if response.Ref.IsSet() {
+		if refResponse, ok := s.Components.Responses[response.Ref.GetName()]; ok {
+			return s.GetResponseMediaTypes(refResponse)
+		}
+	}
+	return response.Content.GetMediaTypes()
+}
+
+func (s Spec) GetResponseMediaType(response Response) MediaType {
+	if mediaTypes := s.GetResponseMediaTypes(response); len(mediaTypes) > 0 {
+		return mediaTypes[0]
+	}
+	return "application/json"
+}
+
+// GetOperationMediaTypes returns media types declared by operation successful responses
+// (or by all of them if there are no successful ones), these are offered to client
+// during content negotiation
+func (s Spec) GetOperationMediaTypes(operation Operation) []MediaType {
+	successContent, content := Content{}, Content{}
+	for status, response := range operation.Responses {
+		if response.IsEmpty() {
+			continue
+		}
+		for _, mediaType := range s.GetResponseMediaTypes(response) {
+			content[mediaType] = response.Content[mediaType]
+			if strings.HasPrefix(status, "2") {
+				successContent[mediaType] = response.Content[mediaType]
+			}
+		}
+	}
+	if len(successContent) > 0 {
+		return successContent.GetMediaTypes()
+	}
+	return content.GetMediaTypes()
+}
+
+// IsNegotiatedOperation reports whether responses of operation declare several media types,
+// media type of response returned by controller is negotiated then as well
+func (s Spec) IsNegotiatedOperation(operation Operation) bool {
+	mediaTypes := make(map[MediaType]bool)
+	for _, response := range operation.Responses {
+		for _, mediaType := range s.GetResponseMediaTypes(response) {
+			mediaTypes[mediaType] = true
+		}
+	}
+	return len(mediaTypes) > 1
+}
+
+func (s Spec) HasNegotiatedOperations() bool {
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			if s.IsNegotiatedOperation(operation) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s Spec) HasEncodedResponses() bool {
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			for _, response := range operation.Responses {
+				if response.IsEmpty() || response.IsStream() || response.IsEventStream() {
+					continue
+				}
+				if s.GetResponseMediaType(response) != "application/json" {
+					return true
+				}
+			}
+		}
+	}
+	return s.HasNegotiatedOperations()
+}
+
+func (s Spec) HasFileUploads() bool {
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			if !operation.HasRequestBodyBindableParameters() {
+				continue
+			}
+			for _, property := range operation.RequestBody.Content.GetBindableParametersSchema().Properties {
+				if property.IsFile() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// HasStyledParameters reports whether some parameter is object or has serialization style or explode
+// other than default of its location, primitives and arrays of default styles need no style helpers
+func (s Spec) HasStyledParameters() bool {
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			for _, parameter := range operation.Parameters {
+				if parameter.GetStyle() != parameter.GetDefaultStyle() || parameter.IsExplode() != (parameter.GetStyle() == "form") {
+					return true
+				}
+				if s.ResolveSchema(parameter.Schema).Type.IsObject() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (s Spec) HasStreamResponses() bool {
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			for _, response := range operation.Responses {
+				if response.IsStream() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (s Spec) HasEventStreamResponses() bool {
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			for _, response := range operation.Responses {
+				if response.IsEventStream() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// HasRequestVariant reports whether component schema has read or write only properties
+// (directly or in referenced schemas), so separate type is generated for requests
+func (s Spec) HasRequestVariant(name string) bool {
+	return s.hasRequestVariant(name, map[string]bool{})
+}
+
+func (s Spec) hasRequestVariant(name string, visited map[string]bool) bool {
+	schema, ok := s.Components.Schemas[name]
+	if !ok || visited[name] {
+		return false
+	}
+	visited[name] = true
+
+	has := false
+	schema.walk(func(inner Schema) {
+		if inner.ReadOnly || inner.WriteOnly {
+			has = true
+		} else if inner.Ref.IsSet() {
+			if component, refName := inner.Ref.GetFullName(); component == "schemas" && s.hasRequestVariant(refName, visited) {
+				has = true
+			}
+		}
+	})
+	return has
+}
+
+func (s Spec) GetRequestTypeName(ref Ref) string {
+	if component, name := ref.GetFullName(); component == "schemas" && s.HasRequestVariant(name) {
+		return name + "RequestSchema"
+	}
+	return ref.GetTypeName()
+}
+
+// walkSchemas calls cb for every schema declared in spec without following references
+func (s Spec) walkSchemas(cb func(Schema)) {
+	for _, schema := range s.Components.Schemas {
+		schema.walk(cb)
+	}
+	for _, response := range s.Components.Responses {
+		response.Content.GetBindableParametersSchema().walk(cb)
+	}
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
+			for _, parameter := range operation.Parameters {
+				parameter.Schema.walk(cb)
+			}
+			operation.RequestBody.Content.GetBindableParametersSchema().walk(cb)
+			for _, response := range operation.Responses {
+				response.Content.GetBindableParametersSchema().walk(cb)
+			}
+		}
+	}
+}
+
+type NullableType struct {
+	Name   string
+	GoType string
+}
+
+func (s Spec) GetNullableTypeName(schema Schema, context string) string {
+	return "Nullable" + strcase.ToCamel(s.getNullableGoType(schema, context))
+}
+
+func (s Spec) getNullableGoType(schema Schema, context string) string {
+	if schema.Ref.IsSet() {
+		if context == PropertiesContextRequestBody || context == PropertiesContextRequestComponents {
+			return s.GetRequestTypeName(schema.Ref)
+		}
+		return schema.Ref.GetTypeName()
+	}
+	return schema.GetGoType()
+}
+
+// GetNullableTypes returns all Nullable types used by generated properties
+func (s Spec) GetNullableTypes() []NullableType {
+	typesMap := make(map[string]NullableType)
+	s.walkSchemas(func(schema Schema) {
+		if !schema.IsNullableValue() {
+			return
+		}
+		for _, context := range []string{PropertiesContextComponents, PropertiesContextRequestComponents} {
+			goType := s.getNullableGoType(schema, context)
+			typesMap[goType] = NullableType{Name: s.GetNullableTypeName(schema, context), GoType: goType}
+		}
+	})
+
+	var types []NullableType
+	for _, t := range typesMap {
+		types = append(types, t)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+
+	return types
+}
+
+// ValidateNullable checks that nullable properties can be generated as Nullable types,
+// inline objects and arrays have no type name to build Nullable type of
+func (s Spec) ValidateNullable() error {
+	var names []string
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var err error
+	check := func(location string) func(Schema) {
+		return func(schema Schema) {
+			for _, name := range schema.GetPropertyNames() {
+				property := schema.Properties[name]
+				if err != nil || !property.Nullable || property.IsNullableValue() {
+					continue
+				}
+				if property.Type.IsObject() || property.Type.IsArray() {
+					err = fmt.Errorf("nullable property %v of %v: inline %v can not be nullable, declare it in components and reference it",
+						name, location, property.Type)
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		s.Components.Schemas[name].walk(check("#/components/schemas/" + name))
+	}
+	for path, item := range s.Paths {
+		for method, operation := range item.Operations {
+			location := OperationId(path, method, operation)
+			operation.RequestBody.Content.GetBindableParametersSchema().walk(check(location + " request body"))
+			for status, response := range operation.Responses {
+				response.Content.GetBindableParametersSchema().walk(check(location + " response " + status))
+			}
+		}
+	}
+
+	return err
+}
+
+// GetExample returns example value of schema: declared example or value composed of
+// examples of properties, items and referenced schemas
+func (s Spec) GetExample(schema Schema) (interface{}, bool) {
+	return s.getExample(schema, map[Ref]bool{})
+}
+
+func (s Spec) getExample(schema Schema, visited map[Ref]bool) (interface{}, bool) {
+	if schema.Example != nil {
+		return schema.Example, true
+	}
+	if len(schema.Examples) > 0 {
+		return schema.Examples[0], true
+	}
+
+	if schema.Ref.IsSet() {
+		if visited[schema.Ref] {
+			return nil, false
+		}
+		visited[schema.Ref] = true
+		defer delete(visited, schema.Ref)
+		return s.getExample(s.GetUnderlyingSchema(schema.Ref), visited)
+	}
+
+	if schema.Type.IsArray() && schema.Items != nil {
+		if item, ok := s.getExample(*schema.Items, visited); ok {
+			return []interface{}{item}, true
+		}
+		return nil, false
+	}
+
+	object := make(map[string]interface{})
+	for _, inner := range schema.AllOf {
+		if value, ok := s.getExample(inner, visited); ok {
+			if innerObject, ok := value.(map[string]interface{}); ok {
+				for name, property := range innerObject {
+					object[name] = property
+				}
+			}
+		}
+	}
+	for name, property := range schema.Properties {
+		if value, ok := s.getExample(property, visited); ok {
+			object[name] = value
+		}
+	}
+	if len(object) == 0 {
+		return nil, false
+	}
+
+	return object, true
+}
+
+// ResolveSchema returns schema that is referenced by given one with flattened allOf composition
+func (s Spec) ResolveSchema(schema Schema) Schema {
+	if schema.Ref.IsSet() {
+		schema = s.GetUnderlyingSchema(schema.Ref)
+	}
+	if schema.IsFlattenedAllOf() {
+		if merged, err := s.MergeAllOf(schema); err == nil {
+			return merged
+		}
+	}
+	return schema
+}
+
+// MergeAllOf flattens allOf composition into single object schema with properties
+// and requirements of all subschemas
+func (s Spec) MergeAllOf(schema Schema) (Schema, error) {
+	return s.mergeAllOf(schema, map[Ref]bool{})
+}
+
+func (s Spec) mergeAllOf(schema Schema, visited map[Ref]bool) (Schema, error) {
+	merged := Schema{
+		Type:        "object",
+		Description: schema.Description,
+		Properties:  make(map[string]Schema),
+	}
+
+	requiredMap := make(map[string]bool)
+
+	subschemas := append([]Schema{}, schema.AllOf...)
+	if len(schema.Properties) > 0 || len(schema.Required) > 0 {
+		// properties declared next to allOf
+		subschemas = append(subschemas, Schema{Type: "object", Properties: schema.Properties, PropertyNames: schema.PropertyNames, Required: schema.Required})
+	}
+
+	for _, subschema := range subschemas {
+		ref := subschema.Ref
+		if ref.IsSet() {
+			if visited[ref] {
+				return Schema{}, fmt.Errorf("schema %v includes itself", ref.GetName())
+			}
+			subschema = s.GetUnderlyingSchema(ref)
+		}
+		if len(subschema.AllOf) > 0 {
+			var err error
+			visited[ref] = true
+			subschema, err = s.mergeAllOf(subschema, visited)
+			delete(visited, ref)
+			if err != nil {
+				return Schema{}, err
+			}
+		}
+
+		for _, name := range subschema.GetPropertyNames() {
+			property := subschema.Properties[name]
+			if existing, ok := merged.Properties[name]; ok {
+				if existing.typeSignature() != property.typeSignature() {
+					return Schema{}, fmt.Errorf("property '%v' has conflicting types %v and %v", name, existing.typeSignature(), property.typeSignature())
+				}
+			} else {
+				merged.PropertyNames = append(merged.PropertyNames, name)
+			}
+			merged.Properties[name] = property
+		}
+
+		for _, name := range subschema.Required {
+			if !requiredMap[name] {
+				requiredMap[name] = true
+				merged.Required = append(merged.Required, name)
+			}
+		}
+
+		if subschema.HasAdditionalProperties() {
+			merged.AdditionalProperties = subschema.AdditionalProperties
+		}
+	}
+
+	return merged, nil
+}
+
+// ValidateAllOf checks that all flattened allOf compositions can be generated
+func (s Spec) ValidateAllOf() error {
+	var names []string
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var err error
+	check := func(location string) func(Schema) {
+		return func(schema Schema) {
+			if err != nil || !schema.IsFlattenedAllOf() {
+				return
+			}
+			if _, mergeErr := s.MergeAllOf(schema); mergeErr != nil {
+				err = fmt.Errorf("allOf of %v: %v", location, mergeErr)
+			}
+		}
+	}
+
+	for _, name := range names {
+		s.Components.Schemas[name].walk(check("#/components/schemas/" + name))
+	}
+	for path, item := range s.Paths {
+		for method, operation := range item.Operations {
+			location := OperationId(path, method, operation)
+			operation.RequestBody.Content.GetBindableParametersSchema().walk(check(location + " request body"))
+			for status, response := range operation.Responses {
+				response.Content.GetBindableParametersSchema().walk(check(location + " response " + status))
+			}
+		}
+	}
+
+	return err
+}
+
 func (s Spec) HasGenericErrorResponse() bool {
 	_, has := s.Components.Responses["Error"]
 	return has
@@ -402,8 +1249,8 @@ func (s Spec) HasGenericErrorResponse() bool {
 
 func (s Spec) GetAllMiddlewareNames() []string {
 	middlewaresMap := make(map[string]bool)
-	for _, operations := range s.Paths {
-		for _, operation := range operations {
+	for _, item := range s.Paths {
+		for _, operation := range item.Operations {
 			for _, middlewareName := range operation.XMiddlewares {
 				middlewaresMap[middlewareName] = true
 			}
@@ -420,32 +1267,30 @@ func (s Spec) GetAllMiddlewareNames() []string {
 	return middlewares
 }
 
+// pathSegmentPattern matches path parameters and words of path segments
+var pathSegmentPattern = regexp.MustCompile(`\{([^}]*)\}|[A-Za-z0-9]+`)
+
+// OperationId returns base name of types and methods generated for operation: x-go-name as is,
+// camel cased operationId or method followed by words of path where parameters are prefixed with By,
+// e.g. GetPetsByPetId for GET /pets/{petId}
 func OperationId(path string, method string, operation Operation) string {
+	if operation.XGoName != "" {
+		return operation.XGoName
+	}
 	if operation.OperationId != "" {
 		return strcase.ToCamel(operation.OperationId)
 	}
 
 	b := strings.Builder{}
+	b.WriteString(strcase.ToCamel(strings.ToLower(method)))
 
-	switch method {
-	case "get":
-		b.WriteString("Get")
-	case "post":
-		b.WriteString("Post")
-	case "put":
-		b.WriteString("Put")
-	case "delete":
-		b.WriteString("Delete")
-	case "update":
-		b.WriteString("Update")
-	}
-
-	r, _ := regexp.Compile("(\\w+)")
-
-	path = strings.ReplaceAll(path, "_", " ")
-	parts := r.FindAllString(path, 10)
-	for _, p := range parts {
-		b.WriteString(strings.Title(p))
+	for _, match := range pathSegmentPattern.FindAllStringSubmatch(path, -1) {
+		if strings.HasPrefix(match[0], "{") {
+			b.WriteString("By")
+			b.WriteString(strcase.ToCamel(match[1]))
+		} else {
+			b.WriteString(strings.Title(match[0]))
+		}
 	}
 
 	return b.String()
@@ -0,0 +1,157 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Generated code follows stable order: paths, operations of path and properties are in order
+// of declaration in spec, responses are ordered by status codes
+// and components are sorted by names
+
+func (s *Spec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Spec
+	if err := unmarshal((*plain)(s)); err != nil {
+		return err
+	}
+
+	var order struct {
+		Paths yaml.MapSlice `yaml:"paths"`
+	}
+	if err := unmarshal(&order); err != nil {
+		return err
+	}
+	s.PathNames = getMapSliceKeys(order.Paths)
+	for path, item := range s.Paths {
+		item.Path = path
+		s.Paths[path] = item
+	}
+
+	return nil
+}
+
+func (s *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Schema
+	if err := unmarshal((*plain)(s)); err != nil {
+		return err
+	}
+
+	if len(s.Properties) == 0 {
+		return nil
+	}
+
+	var order struct {
+		Properties yaml.MapSlice `yaml:"properties"`
+	}
+	if err := unmarshal(&order); err != nil {
+		return err
+	}
+	s.PropertyNames = getMapSliceKeys(order.Properties)
+
+	return nil
+}
+
+func getMapSliceKeys(items yaml.MapSlice) []string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, fmt.Sprint(item.Key))
+	}
+	return keys
+}
+
+// getOrderedKeys returns keys in given order followed by sorted keys missing in it
+func getOrderedKeys(order []string, has func(key string) bool, keys []string) []string {
+	ordered := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range order {
+		if has(key) && !seen[key] {
+			seen[key] = true
+			ordered = append(ordered, key)
+		}
+	}
+
+	var rest []string
+	for _, key := range keys {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(ordered, rest...)
+}
+
+// GetPropertyNames returns names of properties in order of declaration,
+// properties of schemas composed in code follow sorted
+func (s Schema) GetPropertyNames() []string {
+	keys := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		keys = append(keys, name)
+	}
+	return getOrderedKeys(s.PropertyNames, func(name string) bool {
+		_, ok := s.Properties[name]
+		return ok
+	}, keys)
+}
+
+// GetPaths returns path items in order of declaration
+func (s Spec) GetPaths() []PathItem {
+	keys := make([]string, 0, len(s.Paths))
+	for path := range s.Paths {
+		keys = append(keys, path)
+	}
+	paths := getOrderedKeys(s.PathNames, func(path string) bool {
+		_, ok := s.Paths[path]
+		return ok
+	}, keys)
+
+	items := make([]PathItem, 0, len(paths))
+	for _, path := range paths {
+		item := s.Paths[path]
+		item.Path = path
+		items = append(items, item)
+	}
+	return items
+}
+
+// GetMethods returns methods of path item operations in order of declaration
+func (p PathItem) GetMethods() []string {
+	keys := make([]string, 0, len(p.Operations))
+	for method := range p.Operations {
+		keys = append(keys, method)
+	}
+	return getOrderedKeys(p.MethodNames, func(method string) bool {
+		_, ok := p.Operations[method]
+		return ok
+	}, keys)
+}
+
+// GetStatusCodes returns status codes of operation responses: codes in ascending order,
+// range of class like 4XX follows codes of its class and default is the last one
+func (op Operation) GetStatusCodes() []string {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	sort.SliceStable(codes, func(i, j int) bool {
+		return getStatusCodeRank(codes[i]) < getStatusCodeRank(codes[j])
+	})
+	return codes
+}
+
+func getStatusCodeRank(code string) int {
+	if status, err := strconv.Atoi(code); err == nil {
+		return status * 10
+	}
+	if len(code) == 3 && strings.HasSuffix(strings.ToUpper(code), "XX") {
+		if class, err := strconv.Atoi(code[:1]); err == nil {
+			return class*1000 + 999
+		}
+	}
+	return 10000
+}
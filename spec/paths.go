@@ -0,0 +1,156 @@
+package spec
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Methods are HTTP methods of path item operations
+var Methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type ServerVariable struct {
+	Enum        []string `yaml:"enum"`
+	Default     string   `yaml:"default"`
+	Description string   `yaml:"description"`
+}
+
+type Server struct {
+	URL         string                    `yaml:"url"`
+	Description string                    `yaml:"description"`
+	Variables   map[string]ServerVariable `yaml:"variables"`
+}
+
+// GetBasePath returns path component of server URL with variables replaced by their defaults,
+// root path is returned as empty string
+func (s Server) GetBasePath() string {
+	serverURL := s.URL
+	for name, variable := range s.Variables {
+		serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", variable.Default)
+	}
+
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(u.Path, "/")
+}
+
+// PathOperation maps lower case HTTP methods to operations of path
+type PathOperation map[string]Operation
+
+// PathItem describes operations of path, parameters of path item are shared by all of its operations
+type PathItem struct {
+	// Path is key of path item in spec paths
+	Path        string
+	Summary     string
+	Description string
+	Parameters  []Parameter
+	Servers     []Server
+	Operations  PathOperation
+
+	// MethodNames keeps order of operations declaration
+	MethodNames []string
+}
+
+func (p *PathItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var item struct {
+		Summary     string      `yaml:"summary"`
+		Description string      `yaml:"description"`
+		Parameters  []Parameter `yaml:"parameters"`
+		Servers     []Server    `yaml:"servers"`
+		Get         *Operation  `yaml:"get"`
+		Put         *Operation  `yaml:"put"`
+		Post        *Operation  `yaml:"post"`
+		Delete      *Operation  `yaml:"delete"`
+		Options     *Operation  `yaml:"options"`
+		Head        *Operation  `yaml:"head"`
+		Patch       *Operation  `yaml:"patch"`
+		Trace       *Operation  `yaml:"trace"`
+	}
+	if err := unmarshal(&item); err != nil {
+		return err
+	}
+
+	*p = PathItem{
+		Summary:     item.Summary,
+		Description: item.Description,
+		Parameters:  item.Parameters,
+		Servers:     item.Servers,
+		Operations:  make(PathOperation),
+	}
+
+	operations := []*Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch, item.Trace}
+	for i, operation := range operations {
+		if operation != nil {
+			p.Operations[Methods[i]] = p.mergeOperation(*operation)
+		}
+	}
+
+	var order yaml.MapSlice
+	if err := unmarshal(&order); err != nil {
+		return err
+	}
+	p.MethodNames = getMapSliceKeys(order)
+
+	return nil
+}
+
+// mergeOperation adds path item parameters to operation unless operation overrides them by name and location,
+// summary and description of path item are used when operation has none
+func (p PathItem) mergeOperation(operation Operation) Operation {
+	if operation.Summary == "" {
+		operation.Summary = p.Summary
+	}
+	if operation.Description == "" {
+		operation.Description = p.Description
+	}
+
+	if len(p.Parameters) == 0 {
+		return operation
+	}
+
+	overrides := make(map[string]Parameter)
+	for _, parameter := range operation.Parameters {
+		overrides[parameter.In+" "+parameter.Name] = parameter
+	}
+
+	parameters := make([]Parameter, 0, len(p.Parameters)+len(operation.Parameters))
+	for _, parameter := range p.Parameters {
+		key := parameter.In + " " + parameter.Name
+		if override, ok := overrides[key]; ok {
+			parameter = override
+			delete(overrides, key)
+		}
+		parameters = append(parameters, parameter)
+	}
+	for _, parameter := range operation.Parameters {
+		if _, ok := overrides[parameter.In+" "+parameter.Name]; ok {
+			parameters = append(parameters, parameter)
+		}
+	}
+	operation.Parameters = parameters
+
+	return operation
+}
+
+// GetBasePath returns path component of first server of spec, routes may be mounted under it
+func (s Spec) GetBasePath() string {
+	if len(s.Servers) == 0 {
+		return ""
+	}
+	return s.Servers[0].GetBasePath()
+}
+
+// GetOperationBasePath returns base path of operation, servers of operation override servers of path item
+// and they override servers of spec
+func (s Spec) GetOperationBasePath(path string, operation Operation) string {
+	if len(operation.Servers) > 0 {
+		return operation.Servers[0].GetBasePath()
+	}
+	if item, ok := s.Paths[path]; ok && len(item.Servers) > 0 {
+		return item.Servers[0].GetBasePath()
+	}
+	return s.GetBasePath()
+}
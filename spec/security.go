@@ -0,0 +1,156 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of credentials, they define how credentials of security scheme are taken from request
+const (
+	CredentialsAPIKey        = "apiKey"
+	CredentialsBasic         = "basic"
+	CredentialsBearer        = "bearer"
+	CredentialsOAuth2        = "oauth2"
+	CredentialsAuthorization = "authorization"
+)
+
+// SecurityRequirement maps names of security schemes to scopes required by operation,
+// all schemes of requirement should be satisfied
+type SecurityRequirement map[string][]string
+
+// GetSchemeNames returns sorted names of schemes of requirement
+func (r SecurityRequirement) GetSchemeNames() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type OAuthFlow struct {
+	AuthorizationUrl string            `yaml:"authorizationUrl"`
+	TokenUrl         string            `yaml:"tokenUrl"`
+	RefreshUrl       string            `yaml:"refreshUrl"`
+	Scopes           map[string]string `yaml:"scopes"`
+}
+
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `yaml:"implicit"`
+	Password          *OAuthFlow `yaml:"password"`
+	ClientCredentials *OAuthFlow `yaml:"clientCredentials"`
+	AuthorizationCode *OAuthFlow `yaml:"authorizationCode"`
+}
+
+type SecurityScheme struct {
+	Type             string     `yaml:"type"`
+	Description      string     `yaml:"description"`
+	Name             string     `yaml:"name"`
+	In               string     `yaml:"in"`
+	Scheme           string     `yaml:"scheme"`
+	BearerFormat     string     `yaml:"bearerFormat"`
+	Flows            OAuthFlows `yaml:"flows"`
+	OpenIdConnectUrl string     `yaml:"openIdConnectUrl"`
+}
+
+// GetCredentialsKind returns how credentials of scheme are passed: api key in header, query or cookie,
+// username and password of basic authentication, bearer token, access token of OAuth2 and OpenID Connect
+// or raw Authorization header for other HTTP authentication schemes
+func (s SecurityScheme) GetCredentialsKind() string {
+	switch {
+	case s.Type == "apiKey":
+		return CredentialsAPIKey
+	case s.Type == "http" && strings.EqualFold(s.Scheme, "basic"):
+		return CredentialsBasic
+	case s.Type == "http" && strings.EqualFold(s.Scheme, "bearer"):
+		return CredentialsBearer
+	case s.Type == "oauth2", s.Type == "openIdConnect":
+		return CredentialsOAuth2
+	default:
+		return CredentialsAuthorization
+	}
+}
+
+// GetDescription returns description of scheme for doc comments
+func (s SecurityScheme) GetDescription() string {
+	switch s.GetCredentialsKind() {
+	case CredentialsAPIKey:
+		return fmt.Sprintf("api key passed in %v %v", s.In, s.Name)
+	case CredentialsBasic:
+		return "username and password of HTTP basic authentication"
+	case CredentialsOAuth2:
+		if s.Type == "openIdConnect" {
+			return "OpenID Connect token"
+		}
+		return "OAuth2 access token"
+	case CredentialsBearer:
+		if s.BearerFormat != "" {
+			return "bearer token (" + s.BearerFormat + ")"
+		}
+		return "bearer token"
+	default:
+		return "Authorization header of HTTP " + s.Scheme + " authentication"
+	}
+}
+
+// HasSecuritySchemes reports whether spec declares security schemes
+func (s Spec) HasSecuritySchemes() bool {
+	return len(s.Components.SecuritySchemes) > 0
+}
+
+// HasSecurityCredentials reports whether spec declares security scheme with given kind of credentials
+func (s Spec) HasSecurityCredentials(kind string) bool {
+	for _, scheme := range s.Components.SecuritySchemes {
+		if scheme.GetCredentialsKind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOperationSecurity returns alternative security requirements of operation,
+// operation security overrides spec security and empty list means operation is public
+func (s Spec) GetOperationSecurity(op Operation) []SecurityRequirement {
+	if op.Security != nil {
+		return *op.Security
+	}
+	return s.Security
+}
+
+// ValidateSecurity checks that security requirements refer to declared security schemes
+func (s Spec) ValidateSecurity() error {
+	check := func(location string, requirements []SecurityRequirement) error {
+		for _, requirement := range requirements {
+			for _, name := range requirement.GetSchemeNames() {
+				if _, ok := s.Components.SecuritySchemes[name]; !ok {
+					return fmt.Errorf("%v: unknown security scheme '%v'", location, name)
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := check("security", s.Security); err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(s.Paths))
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for method, operation := range s.Paths[path].Operations {
+			if operation.Security == nil {
+				continue
+			}
+			if err := check(strings.ToUpper(method)+" "+path, *operation.Security); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
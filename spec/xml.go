@@ -0,0 +1,65 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ValidateXMLContent checks that schemas of XML media types can be encoded by encoding/xml,
+// objects with additionalProperties only are generated as maps which it does not support
+func (s Spec) ValidateXMLContent() error {
+	var names []string
+	for name := range s.Components.Responses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var err error
+	check := func(content Content, location string) {
+		for _, mediaType := range content.GetMediaTypes() {
+			if err != nil || !mediaType.IsXML() {
+				continue
+			}
+			if name, ok := s.findMapSchema(content[mediaType].Schema, location, map[string]bool{}); ok {
+				err = fmt.Errorf("XML content of %v: %v is map which can not be encoded as XML", location, name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		check(s.Components.Responses[name].Content, "#/components/responses/"+name)
+	}
+	for path, item := range s.Paths {
+		for method, operation := range item.Operations {
+			location := OperationId(path, method, operation)
+			check(operation.RequestBody.Content, location+" request body")
+			for status, response := range operation.Responses {
+				check(response.Content, location+" response "+status)
+			}
+		}
+	}
+
+	return err
+}
+
+// findMapSchema returns location of first map schema inside of schema following references
+func (s Spec) findMapSchema(schema Schema, location string, visited map[string]bool) (string, bool) {
+	found, ok := "", false
+	schema.walk(func(inner Schema) {
+		if ok {
+			return
+		}
+		if inner.IsMap() {
+			found, ok = location, true
+			return
+		}
+		if !inner.Ref.IsSet() {
+			return
+		}
+		if typ, name := inner.Ref.GetFullName(); typ == "schemas" && !visited[name] {
+			visited[name] = true
+			found, ok = s.findMapSchema(s.Components.Schemas[name], componentsSchemasPrefix+name, visited)
+		}
+	})
+	return found, ok
+}